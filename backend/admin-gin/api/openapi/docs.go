@@ -26,7 +26,7 @@ const docTemplate = `{
     "paths": {
         "/admin/auth/login": {
             "post": {
-                "description": "用户名/密码登录",
+                "description": "用户名/密码登录；用户不存在与密码错误统一返回 401，失败次数过多时用户名 / IP 被临时锁定并返回 429；账号已禁用 / 删除返回 403\n已启用两步验证，或所属角色强制两步验证时，data 为 mfaChallengeRes（mfa_token / setup_required）而非 token 对，需调用 /admin/auth/login/mfa 完成登录\n管理员要求下次登录修改密码时，返回 must_change_password=true，此时除修改密码、注销外的接口均返回 403",
                 "consumes": [
                     "application/json"
                 ],
@@ -59,9 +59,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/auth/register": {
+        "/admin/auth/login/mfa": {
             "post": {
-                "description": "用户名/密码进行注册",
+                "description": "凭登录返回的 mfa_token 提交 6 位 TOTP 验证码或恢复码完成登录；强制绑定（setup_required）时提交绑定后的 TOTP 验证码，响应额外返回恢复码\n验证码错误计入登录失败次数，同一 mfa_token 错误 5 次后失效",
                 "consumes": [
                     "application/json"
                 ],
@@ -71,37 +71,101 @@ const docTemplate = `{
                 "tags": [
                     "Auth"
                 ],
-                "summary": "用户注册",
-                "operationId": "register",
+                "summary": "两步验证登录",
+                "operationId": "loginMFA",
                 "parameters": [
                     {
-                        "description": "注册参数",
+                        "description": "验证参数",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/auth.registerReq"
+                            "$ref": "#/definitions/auth.mfaLoginReq"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "注册成功",
+                        "description": "登录成功",
                         "schema": {
-                            "$ref": "#/definitions/http.HttpResponse-Empty"
+                            "$ref": "#/definitions/http.HttpResponse-auth_mfaLoginRes"
                         }
                     }
                 }
             }
         },
-        "/admin/auth/session/logout": {
+        "/admin/auth/login/mfa/setup": {
+            "post": {
+                "description": "所属角色强制两步验证但尚未绑定时，凭 mfa_token 生成 TOTP 密钥；在身份验证器 App 中添加后调用 /admin/auth/login/mfa 完成绑定与登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "登录时绑定两步验证",
+                "operationId": "loginMFASetup",
+                "parameters": [
+                    {
+                        "description": "挑战令牌",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.mfaTokenReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "生成成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-auth_mfaSetupRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/auth/mfa": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "查询当前用户是否已启用两步验证、所属角色是否强制两步验证，以及剩余恢复码数量",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "两步验证状态",
+                "operationId": "mfaStatus",
+                "responses": {
+                    "200": {
+                        "description": "查询成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-auth_mfaStatusRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/auth/mfa/confirm": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "用户注销,同时移除刷新令牌",
+                "description": "提交身份验证器 App 生成的 6 位验证码，校验通过后启用两步验证并返回恢复码（仅展示一次）",
                 "consumes": [
                     "application/json"
                 ],
@@ -111,35 +175,37 @@ const docTemplate = `{
                 "tags": [
                     "Auth"
                 ],
-                "summary": "用户注销",
-                "operationId": "logout",
+                "summary": "启用两步验证",
+                "operationId": "mfaConfirm",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "格式: refresh_token=xxx",
-                        "name": "Cookie",
-                        "in": "header",
-                        "required": true
+                        "description": "验证码",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.mfaCodeReq"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "注销成功",
+                        "description": "启用成功",
                         "schema": {
-                            "$ref": "#/definitions/http.HttpResponse-Empty"
+                            "$ref": "#/definitions/http.HttpResponse-auth_recoveryCodesRes"
                         }
                     }
                 }
             }
         },
-        "/admin/auth/session/refresh": {
+        "/admin/auth/mfa/disable": {
             "post": {
                 "security": [
                     {
-                        "CookieAuth": []
+                        "BearerAuth": []
                     }
                 ],
-                "description": "用于短期令牌 Access_Token 续期",
+                "description": "提交 TOTP 验证码或恢复码关闭两步验证；所属角色强制两步验证时返回 403",
                 "consumes": [
                     "application/json"
                 ],
@@ -149,35 +215,37 @@ const docTemplate = `{
                 "tags": [
                     "Auth"
                 ],
-                "summary": "刷新令牌",
-                "operationId": "refreshToken",
+                "summary": "关闭两步验证",
+                "operationId": "mfaDisable",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "格式: refresh_token=xxx",
-                        "name": "Cookie",
-                        "in": "header",
-                        "required": true
+                        "description": "验证码",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.mfaCodeReq"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "刷新成功",
+                        "description": "关闭成功",
                         "schema": {
-                            "$ref": "#/definitions/http.HttpResponse-auth_loginRes"
+                            "$ref": "#/definitions/http.HttpResponse-Empty"
                         }
                     }
                 }
             }
         },
-        "/admin/user": {
-            "get": {
+        "/admin/auth/mfa/recovery-codes": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "支持分页以及条件查询",
+                "description": "提交 TOTP 验证码或恢复码，校验通过后旧恢复码全部作废并返回新的恢复码（仅展示一次）",
                 "consumes": [
                     "application/json"
                 ],
@@ -185,207 +253,2828 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Auth"
                 ],
-                "summary": "获取用户列表",
-                "operationId": "listUser",
+                "summary": "重新生成恢复码",
+                "operationId": "mfaRecoveryCodes",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "关键字：多字段综合搜索， email/username/uid",
-                        "name": "keyword",
-                        "in": "query"
-                    },
+                        "description": "验证码",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.mfaCodeReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "生成成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-auth_recoveryCodesRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/auth/mfa/setup": {
+            "post": {
+                "security": [
                     {
-                        "minimum": 1,
-                        "type": "integer",
-                        "example": 1,
-                        "description": "当前页码，默认 1",
-                        "name": "page",
-                        "in": "query"
-                    },
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "生成待启用的 TOTP 密钥与 otpauth URI，调用 /admin/auth/mfa/confirm 校验验证码后生效；重复调用会替换未启用的密钥",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "生成两步验证密钥",
+                "operationId": "mfaSetup",
+                "responses": {
+                    "200": {
+                        "description": "生成成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-auth_mfaSetupRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/auth/password": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "校验原密码后修改密码；新密码需满足密码策略且不能与最近使用过的密码相同（422），原密码错误返回 400 并计入登录失败次数\n修改成功后解除 \"须修改密码\" 限制，并下线当前会话以外的全部会话",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "修改密码",
+                "operationId": "changePassword",
+                "parameters": [
+                    {
+                        "description": "修改密码参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.changePasswordReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "修改成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-Empty"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/auth/password/forgot": {
+            "post": {
+                "description": "向邮箱发送重置密码链接（默认 30 分钟内有效，仅可使用一次，再次申请时旧链接作废）\n无论邮箱是否存在、账号是否可用均返回成功，避免枚举邮箱；同一账号 1 分钟内只发送一次",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "找回密码",
+                "operationId": "forgotPassword",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "角色：枚举",
-                        "name": "role",
-                        "in": "query"
+                        "description": "邮件语言（请求参数 lang 为空时生效）",
+                        "name": "Accept-Language",
+                        "in": "header"
                     },
                     {
-                        "maximum": 100,
-                        "minimum": 1,
-                        "type": "integer",
-                        "example": 10,
-                        "description": "每页数量，默认 10，最大 100",
-                        "name": "size",
-                        "in": "query"
+                        "description": "找回密码参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.forgotPasswordReq"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "查询成功",
+                        "description": "如邮箱已绑定账号，将收到重置邮件",
                         "schema": {
-                            "$ref": "#/definitions/http.HttpResponse-http_PageRes-user_listRes"
+                            "$ref": "#/definitions/http.HttpResponse-Empty"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "auth.loginReq": {
-            "type": "object",
-            "required": [
-                "password",
-                "username"
-            ],
-            "properties": {
-                "password": {
-                    "description": "密码",
-                    "type": "string",
-                    "example": "123456"
-                },
-                "username": {
-                    "description": "用户名",
-                    "type": "string",
-                    "example": "admin"
+        },
+        "/admin/auth/password/reset": {
+            "post": {
+                "description": "凭重置邮件中的令牌设置新密码；令牌无效、已过期或已使用返回 400，新密码不满足密码策略返回 422（令牌仍可继续使用）\n重置成功后解除 \"须修改密码\" 限制与登录锁定，并下线该用户的全部会话",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "重置密码",
+                "operationId": "resetPassword",
+                "parameters": [
+                    {
+                        "description": "重置密码参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.resetPasswordReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "重置成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-Empty"
+                        }
+                    }
                 }
             }
         },
-        "auth.loginRes": {
+        "/admin/auth/register": {
+            "post": {
+                "description": "用户名/密码进行注册；未开放注册（auth.allow_register=false）时返回 403，密码不满足密码策略时返回 422",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "用户注册",
+                "operationId": "register",
+                "parameters": [
+                    {
+                        "description": "注册参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.registerReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "注册成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-Empty"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/auth/session": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "查询当前用户在各设备上的登录会话，current 标记当前会话",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "在线会话列表",
+                "operationId": "listSession",
+                "responses": {
+                    "200": {
+                        "description": "查询成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-array_auth_sessionRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/auth/session/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "用户注销,同时移除刷新令牌",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "用户注销",
+                "operationId": "logout",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "格式: refresh_token=xxx",
+                        "name": "Cookie",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "注销成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-Empty"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/auth/session/others": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "保留当前会话，使其他设备上的会话全部失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "下线其他会话",
+                "operationId": "revokeOtherSessions",
+                "responses": {
+                    "200": {
+                        "description": "下线成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-Empty"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/auth/session/refresh": {
+            "post": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "用于短期令牌 Access_Token 续期；每次刷新都会轮换 refresh_token cookie，重复使用已轮换的 refresh_token 将导致该会话被下线",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "刷新令牌",
+                "operationId": "refreshToken",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "格式: refresh_token=xxx",
+                        "name": "Cookie",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "刷新成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-auth_loginRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/auth/session/{sid}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "使指定会话的刷新令牌失效，该设备需重新登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "下线指定会话",
+                "operationId": "revokeSession",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "会话标识",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "下线成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-Empty"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/auth/unlock": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "清除用户名（以及可选的 IP）的登录失败计数与锁定",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "解除登录锁定",
+                "operationId": "unlockLogin",
+                "parameters": [
+                    {
+                        "description": "解锁参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.unlockReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "解锁成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-Empty"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/iam/menu": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "完整菜单树（含所有菜单），用于菜单管理",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Menu"
+                ],
+                "summary": "获取菜单树",
+                "operationId": "listMenu",
+                "responses": {
+                    "200": {
+                        "description": "查询成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-array_menu_menuRes"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "parent_id 为 0 表示顶级菜单",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Menu"
+                ],
+                "summary": "新增菜单",
+                "operationId": "createMenu",
+                "parameters": [
+                    {
+                        "description": "菜单参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/menu.createReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "新增成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-Empty"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/iam/menu/mine": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "按当前用户角色所拥有的权限点过滤后的菜单树，前端据此生成导航",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Menu"
+                ],
+                "summary": "获取当前用户菜单",
+                "operationId": "myMenu",
+                "responses": {
+                    "200": {
+                        "description": "查询成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-array_menu_menuRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/iam/menu/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "支持移动父节点（不能移动到自身或其子菜单下）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Menu"
+                ],
+                "summary": "修改菜单",
+                "operationId": "updateMenu",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "菜单 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "菜单参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/menu.updateReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "修改成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-Empty"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "存在子菜单时不可删除",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Menu"
+                ],
+                "summary": "删除菜单",
+                "operationId": "deleteMenu",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "菜单 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-Empty"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/iam/permission": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回全部权限点，按模块排序",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Permission"
+                ],
+                "summary": "获取权限点列表",
+                "operationId": "listPermission",
+                "responses": {
+                    "200": {
+                        "description": "查询成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-array_role_permissionRes"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "权限点标识格式 \"资源:动作\"，需与路由声明保持一致",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Permission"
+                ],
+                "summary": "新增权限点",
+                "operationId": "createPermission",
+                "parameters": [
+                    {
+                        "description": "权限点参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/role.createPermissionReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "新增成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-Empty"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/iam/permission/mine": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回当前登录用户通过已启用角色获得的权限点，前端据此控制按钮等元素的显示",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Permission"
+                ],
+                "summary": "获取当前用户权限点",
+                "operationId": "myPermission",
+                "responses": {
+                    "200": {
+                        "description": "查询成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-array_string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/iam/permission/{code}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "支持修改名称、模块、备注",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Permission"
+                ],
+                "summary": "修改权限点",
+                "operationId": "updatePermission",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "权限点标识",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "权限点参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/role.updatePermissionReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "修改成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-Empty"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "同时解除与所有角色的绑定",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Permission"
+                ],
+                "summary": "删除权限点",
+                "operationId": "deletePermission",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "权限点标识",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-Empty"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/iam/role": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "支持分页以及关键字查询",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "获取角色列表",
+                "operationId": "listRole",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "关键字：匹配 code/name",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 1,
+                        "description": "当前页码，默认 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 10,
+                        "description": "每页数量，默认 10，最大 100",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "查询成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-http_PageRes-role_roleRes"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "角色标识创建后不可修改",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "新增角色",
+                "operationId": "createRole",
+                "parameters": [
+                    {
+                        "description": "角色参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/role.createReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "新增成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-Empty"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/iam/role/{code}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "支持修改名称、排序、启用状态、备注；超级管理员角色不可禁用、不可关闭两步验证要求（403）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "修改角色",
+                "operationId": "updateRole",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "角色标识",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "角色参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/role.updateReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "修改成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-Empty"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "内置角色、仍有关联用户的角色不可删除",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "删除角色",
+                "operationId": "deleteRole",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "角色标识",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-Empty"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/iam/role/{code}/permission": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回角色已绑定的权限点标识",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "获取角色权限点",
+                "operationId": "listRolePermission",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "角色标识",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "查询成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-array_string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "全量覆盖角色的权限点，传空数组表示清空\n超级管理员角色的权限点不可修改，通配权限点 * 不可绑定到其他角色（403）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "设置角色权限点",
+                "operationId": "setRolePermission",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "角色标识",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "权限点参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/role.setPermissionsReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "设置成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-Empty"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/system/log-level": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回全局默认级别与各组件（如 gorm、gin、auth）的生效级别",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "查询日志级别",
+                "operationId": "logLevels",
+                "responses": {
+                    "200": {
+                        "description": "查询成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-system_logLevelRes"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "运行时修改全局默认级别或单个组件的级别，立即生效，无需重启；level 为空时移除组件的单独设置\n仅修改处理该请求的实例，重启后恢复为配置文件中的级别",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "修改日志级别",
+                "operationId": "setLogLevel",
+                "parameters": [
+                    {
+                        "description": "级别参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/system.setLogLevelReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "修改成功，返回修改后的级别",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-system_logLevelRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/user": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "支持分页以及条件查询",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "获取用户列表",
+                "operationId": "listUser",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "关键字：多字段综合搜索， email/username/uid",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 1,
+                        "description": "当前页码，默认 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "角色标识：筛选拥有该角色的用户",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 10,
+                        "description": "每页数量，默认 10，最大 100",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "查询成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-http_PageRes-user_listRes"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "创建后台用户并绑定角色；密码需满足密码策略，用户名 / 邮箱已存在返回 409，角色未定义返回 422\n仅超级管理员可创建拥有 super_admin 角色的用户；授予的角色含操作人未拥有的权限点时返回 403",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "创建用户",
+                "operationId": "createUser",
+                "parameters": [
+                    {
+                        "description": "用户信息",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.CreateReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "创建成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-user_CreateRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/user/recycle": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "分页查询已删除的用户，按删除时间倒序；purge_at 为预计彻底删除时间",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "回收站列表",
+                "operationId": "listDeletedUser",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "关键字：多字段综合搜索， email/username/uid",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 1,
+                        "description": "当前页码，默认 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 10,
+                        "description": "每页数量，默认 10，最大 100",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "查询成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-http_PageRes-user_recycleRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/user/{uid}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "按 UID 查询用户详情（含角色），已删除用户返回 404",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "获取用户详情",
+                "operationId": "getUser",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户 UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "查询成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-user_listRes"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "部分更新邮箱、角色（全量覆盖）、启用状态、须修改密码标记；禁用后该用户已签发的令牌立即失效\n不能禁用自己或修改自己的角色，不能禁用最后一个可用的超级管理员或移除其超级管理员角色（403）\n仅超级管理员可修改超级管理员账号、授予或移除 super_admin 角色；新增的角色含操作人未拥有的权限点时返回 403",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "更新用户",
+                "operationId": "updateUser",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户 UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "更新内容",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.UpdateReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "更新成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-user_UpdateRes"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "软删除用户（移入回收站），该用户已签发的令牌立即失效；不能删除自己，不能删除最后一个可用的超级管理员（403）\n仅超级管理员可删除超级管理员（403）\n回收站中的用户可在保留期内恢复，超过保留期后被彻底删除；删除后用户名 / 邮箱可被新用户使用",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "删除用户",
+                "operationId": "deleteUser",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户 UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-user_DeleteRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/user/{uid}/password": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "管理员重置用户密码：指定的 password 需满足密码策略且不能与最近使用过的密码相同（422）；不传时生成随机临时密码并在响应中返回（仅返回一次）\n重置后该用户下次登录须修改密码，已签发的令牌立即失效；不能重置自己的密码（403，请使用 /admin/auth/password）\n仅超级管理员可重置超级管理员的密码（403）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "重置用户密码",
+                "operationId": "resetUserPassword",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户 UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "新密码（可选）",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.ResetPasswordReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "重置成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-user_ResetPasswordRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/user/{uid}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "从回收站恢复用户（角色保持删除前的设置），用户需重新登录；用户名或邮箱已被其他用户使用时返回 409\n仅超级管理员可恢复超级管理员（403）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "恢复用户",
+                "operationId": "restoreUser",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户 UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "恢复成功",
+                        "schema": {
+                            "$ref": "#/definitions/http.HttpResponse-Empty"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "auth.changePasswordReq": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
+            "properties": {
+                "new_password": {
+                    "description": "新密码：需满足密码策略，且不能与最近使用过的密码相同",
+                    "type": "string"
+                },
+                "old_password": {
+                    "description": "原密码",
+                    "type": "string"
+                }
+            }
+        },
+        "auth.forgotPasswordReq": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "description": "账号绑定的邮箱",
+                    "type": "string",
+                    "maxLength": 128,
+                    "example": "admin@example.com"
+                },
+                "lang": {
+                    "description": "邮件语言：zh / en，留空时按 Accept-Language 选择",
+                    "type": "string",
+                    "maxLength": 16,
+                    "example": "zh"
+                }
+            }
+        },
+        "auth.loginReq": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "description": "密码",
+                    "type": "string",
+                    "example": "123456"
+                },
+                "username": {
+                    "description": "用户名",
+                    "type": "string",
+                    "example": "admin"
+                }
+            }
+        },
+        "auth.loginRes": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "description": "访问令牌: 15分钟过期",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "过期时间：访问令牌 Access_token 过期时间(秒)",
+                    "type": "integer"
+                },
+                "must_change_password": {
+                    "description": "须修改密码：为 true 时除修改密码、注销外的接口均返回 403，前端应跳转修改密码页",
+                    "type": "boolean"
+                },
+                "uid": {
+                    "description": "用户UID",
+                    "type": "string"
+                }
+            }
+        },
+        "auth.mfaCodeReq": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "description": "6 位 TOTP 验证码或恢复码",
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "123456"
+                }
+            }
+        },
+        "auth.mfaLoginReq": {
+            "type": "object",
+            "required": [
+                "code",
+                "mfa_token"
+            ],
+            "properties": {
+                "code": {
+                    "description": "6 位 TOTP 验证码或恢复码（强制绑定时只能使用 TOTP 验证码）",
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "123456"
+                },
+                "mfa_token": {
+                    "description": "登录返回的两步验证挑战令牌",
+                    "type": "string"
+                }
+            }
+        },
+        "auth.mfaLoginRes": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "description": "访问令牌: 15分钟过期",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "过期时间：访问令牌 Access_token 过期时间(秒)",
+                    "type": "integer"
+                },
+                "must_change_password": {
+                    "description": "须修改密码：为 true 时除修改密码、注销外的接口均返回 403，前端应跳转修改密码页",
+                    "type": "boolean"
+                },
+                "recovery_codes": {
+                    "description": "强制绑定时返回的恢复码，仅展示一次",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "uid": {
+                    "description": "用户UID",
+                    "type": "string"
+                }
+            }
+        },
+        "auth.mfaSetupRes": {
+            "type": "object",
+            "properties": {
+                "otpauth_url": {
+                    "description": "otpauth:// URI，前端生成二维码供身份验证器 App 扫描",
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "TOTP 密钥（Base32），无法扫码时手动输入",
+                    "type": "string"
+                }
+            }
+        },
+        "auth.mfaStatusRes": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "是否已启用两步验证",
+                    "type": "boolean"
+                },
+                "recovery_codes": {
+                    "description": "剩余可用恢复码数量",
+                    "type": "integer"
+                },
+                "required": {
+                    "description": "所属角色是否强制两步验证（强制时不可关闭）",
+                    "type": "boolean"
+                }
+            }
+        },
+        "auth.mfaTokenReq": {
+            "type": "object",
+            "required": [
+                "mfa_token"
+            ],
+            "properties": {
+                "mfa_token": {
+                    "description": "登录返回的两步验证挑战令牌",
+                    "type": "string"
+                }
+            }
+        },
+        "auth.recoveryCodesRes": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "description": "恢复码：每个仅可使用一次，仅展示一次，请妥善保存",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "auth.registerReq": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "description": "密码",
+                    "type": "string",
+                    "example": "123456"
+                },
+                "username": {
+                    "description": "用户名",
+                    "type": "string",
+                    "example": "admin"
+                }
+            }
+        },
+        "auth.resetPasswordReq": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "token"
+            ],
+            "properties": {
+                "new_password": {
+                    "description": "新密码：需满足密码策略，且不能与最近使用过的密码相同",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "重置邮件链接中的 token 参数",
+                    "type": "string",
+                    "maxLength": 128
+                }
+            }
+        },
+        "auth.sessionRes": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "登录时间",
+                    "type": "string"
+                },
+                "current": {
+                    "description": "是否为当前请求所属会话",
+                    "type": "boolean"
+                },
+                "device": {
+                    "description": "设备：由 User-Agent 识别，如 \"Chrome / macOS\"",
+                    "type": "string"
+                },
+                "ip": {
+                    "description": "最近一次登录/刷新的 IP",
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "description": "最近活跃时间（登录、刷新令牌时更新）",
+                    "type": "string"
+                },
+                "sid": {
+                    "description": "会话标识",
+                    "type": "string"
+                },
+                "user_agent": {
+                    "description": "登录时的 User-Agent",
+                    "type": "string"
+                }
+            }
+        },
+        "auth.unlockReq": {
+            "type": "object",
+            "required": [
+                "username"
+            ],
+            "properties": {
+                "ip": {
+                    "description": "可选：同时解除该 IP 的锁定",
+                    "type": "string",
+                    "example": "127.0.0.1"
+                },
+                "username": {
+                    "description": "被锁定的用户名",
+                    "type": "string",
+                    "example": "admin"
+                }
+            }
+        },
+        "http.Empty": {
+            "type": "object"
+        },
+        "http.HttpResponse-Empty": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "code: HTTP 状态码",
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "description": "data: 响应数据（可以为空）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/http.Empty"
+                        }
+                    ]
+                },
+                "message": {
+                    "description": "message: 响应描述",
+                    "type": "string",
+                    "example": "操作成功"
+                },
+                "request_id": {
+                    "description": "request_id: 请求 ID（仅失败响应返回，与响应头 X-Request-ID 一致），反馈问题时提供以便排查日志",
+                    "type": "string",
+                    "example": "9f1c2d3e4b5a69788796a5b4c3d2e1f0"
+                }
+            }
+        },
+        "http.HttpResponse-array_auth_sessionRes": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "code: HTTP 状态码",
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "description": "data: 响应数据（可以为空）",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/auth.sessionRes"
+                    }
+                },
+                "message": {
+                    "description": "message: 响应描述",
+                    "type": "string",
+                    "example": "操作成功"
+                },
+                "request_id": {
+                    "description": "request_id: 请求 ID（仅失败响应返回，与响应头 X-Request-ID 一致），反馈问题时提供以便排查日志",
+                    "type": "string",
+                    "example": "9f1c2d3e4b5a69788796a5b4c3d2e1f0"
+                }
+            }
+        },
+        "http.HttpResponse-array_menu_menuRes": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "code: HTTP 状态码",
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "description": "data: 响应数据（可以为空）",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/menu.menuRes"
+                    }
+                },
+                "message": {
+                    "description": "message: 响应描述",
+                    "type": "string",
+                    "example": "操作成功"
+                },
+                "request_id": {
+                    "description": "request_id: 请求 ID（仅失败响应返回，与响应头 X-Request-ID 一致），反馈问题时提供以便排查日志",
+                    "type": "string",
+                    "example": "9f1c2d3e4b5a69788796a5b4c3d2e1f0"
+                }
+            }
+        },
+        "http.HttpResponse-array_role_permissionRes": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "code: HTTP 状态码",
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "description": "data: 响应数据（可以为空）",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/role.permissionRes"
+                    }
+                },
+                "message": {
+                    "description": "message: 响应描述",
+                    "type": "string",
+                    "example": "操作成功"
+                },
+                "request_id": {
+                    "description": "request_id: 请求 ID（仅失败响应返回，与响应头 X-Request-ID 一致），反馈问题时提供以便排查日志",
+                    "type": "string",
+                    "example": "9f1c2d3e4b5a69788796a5b4c3d2e1f0"
+                }
+            }
+        },
+        "http.HttpResponse-array_string": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "code: HTTP 状态码",
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "description": "data: 响应数据（可以为空）",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "message": {
+                    "description": "message: 响应描述",
+                    "type": "string",
+                    "example": "操作成功"
+                },
+                "request_id": {
+                    "description": "request_id: 请求 ID（仅失败响应返回，与响应头 X-Request-ID 一致），反馈问题时提供以便排查日志",
+                    "type": "string",
+                    "example": "9f1c2d3e4b5a69788796a5b4c3d2e1f0"
+                }
+            }
+        },
+        "http.HttpResponse-auth_loginRes": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "code: HTTP 状态码",
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "description": "data: 响应数据（可以为空）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/auth.loginRes"
+                        }
+                    ]
+                },
+                "message": {
+                    "description": "message: 响应描述",
+                    "type": "string",
+                    "example": "操作成功"
+                },
+                "request_id": {
+                    "description": "request_id: 请求 ID（仅失败响应返回，与响应头 X-Request-ID 一致），反馈问题时提供以便排查日志",
+                    "type": "string",
+                    "example": "9f1c2d3e4b5a69788796a5b4c3d2e1f0"
+                }
+            }
+        },
+        "http.HttpResponse-auth_mfaLoginRes": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "code: HTTP 状态码",
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "description": "data: 响应数据（可以为空）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/auth.mfaLoginRes"
+                        }
+                    ]
+                },
+                "message": {
+                    "description": "message: 响应描述",
+                    "type": "string",
+                    "example": "操作成功"
+                },
+                "request_id": {
+                    "description": "request_id: 请求 ID（仅失败响应返回，与响应头 X-Request-ID 一致），反馈问题时提供以便排查日志",
+                    "type": "string",
+                    "example": "9f1c2d3e4b5a69788796a5b4c3d2e1f0"
+                }
+            }
+        },
+        "http.HttpResponse-auth_mfaSetupRes": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "code: HTTP 状态码",
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "description": "data: 响应数据（可以为空）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/auth.mfaSetupRes"
+                        }
+                    ]
+                },
+                "message": {
+                    "description": "message: 响应描述",
+                    "type": "string",
+                    "example": "操作成功"
+                },
+                "request_id": {
+                    "description": "request_id: 请求 ID（仅失败响应返回，与响应头 X-Request-ID 一致），反馈问题时提供以便排查日志",
+                    "type": "string",
+                    "example": "9f1c2d3e4b5a69788796a5b4c3d2e1f0"
+                }
+            }
+        },
+        "http.HttpResponse-auth_mfaStatusRes": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "code: HTTP 状态码",
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "description": "data: 响应数据（可以为空）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/auth.mfaStatusRes"
+                        }
+                    ]
+                },
+                "message": {
+                    "description": "message: 响应描述",
+                    "type": "string",
+                    "example": "操作成功"
+                },
+                "request_id": {
+                    "description": "request_id: 请求 ID（仅失败响应返回，与响应头 X-Request-ID 一致），反馈问题时提供以便排查日志",
+                    "type": "string",
+                    "example": "9f1c2d3e4b5a69788796a5b4c3d2e1f0"
+                }
+            }
+        },
+        "http.HttpResponse-auth_recoveryCodesRes": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "code: HTTP 状态码",
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "description": "data: 响应数据（可以为空）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/auth.recoveryCodesRes"
+                        }
+                    ]
+                },
+                "message": {
+                    "description": "message: 响应描述",
+                    "type": "string",
+                    "example": "操作成功"
+                },
+                "request_id": {
+                    "description": "request_id: 请求 ID（仅失败响应返回，与响应头 X-Request-ID 一致），反馈问题时提供以便排查日志",
+                    "type": "string",
+                    "example": "9f1c2d3e4b5a69788796a5b4c3d2e1f0"
+                }
+            }
+        },
+        "http.HttpResponse-http_PageRes-role_roleRes": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "code: HTTP 状态码",
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "description": "data: 响应数据（可以为空）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/http.PageRes-role_roleRes"
+                        }
+                    ]
+                },
+                "message": {
+                    "description": "message: 响应描述",
+                    "type": "string",
+                    "example": "操作成功"
+                },
+                "request_id": {
+                    "description": "request_id: 请求 ID（仅失败响应返回，与响应头 X-Request-ID 一致），反馈问题时提供以便排查日志",
+                    "type": "string",
+                    "example": "9f1c2d3e4b5a69788796a5b4c3d2e1f0"
+                }
+            }
+        },
+        "http.HttpResponse-http_PageRes-user_listRes": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "code: HTTP 状态码",
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "description": "data: 响应数据（可以为空）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/http.PageRes-user_listRes"
+                        }
+                    ]
+                },
+                "message": {
+                    "description": "message: 响应描述",
+                    "type": "string",
+                    "example": "操作成功"
+                },
+                "request_id": {
+                    "description": "request_id: 请求 ID（仅失败响应返回，与响应头 X-Request-ID 一致），反馈问题时提供以便排查日志",
+                    "type": "string",
+                    "example": "9f1c2d3e4b5a69788796a5b4c3d2e1f0"
+                }
+            }
+        },
+        "http.HttpResponse-http_PageRes-user_recycleRes": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "code: HTTP 状态码",
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "description": "data: 响应数据（可以为空）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/http.PageRes-user_recycleRes"
+                        }
+                    ]
+                },
+                "message": {
+                    "description": "message: 响应描述",
+                    "type": "string",
+                    "example": "操作成功"
+                },
+                "request_id": {
+                    "description": "request_id: 请求 ID（仅失败响应返回，与响应头 X-Request-ID 一致），反馈问题时提供以便排查日志",
+                    "type": "string",
+                    "example": "9f1c2d3e4b5a69788796a5b4c3d2e1f0"
+                }
+            }
+        },
+        "http.HttpResponse-system_logLevelRes": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "code: HTTP 状态码",
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "description": "data: 响应数据（可以为空）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/system.logLevelRes"
+                        }
+                    ]
+                },
+                "message": {
+                    "description": "message: 响应描述",
+                    "type": "string",
+                    "example": "操作成功"
+                },
+                "request_id": {
+                    "description": "request_id: 请求 ID（仅失败响应返回，与响应头 X-Request-ID 一致），反馈问题时提供以便排查日志",
+                    "type": "string",
+                    "example": "9f1c2d3e4b5a69788796a5b4c3d2e1f0"
+                }
+            }
+        },
+        "http.HttpResponse-user_CreateRes": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "code: HTTP 状态码",
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "description": "data: 响应数据（可以为空）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/user.CreateRes"
+                        }
+                    ]
+                },
+                "message": {
+                    "description": "message: 响应描述",
+                    "type": "string",
+                    "example": "操作成功"
+                },
+                "request_id": {
+                    "description": "request_id: 请求 ID（仅失败响应返回，与响应头 X-Request-ID 一致），反馈问题时提供以便排查日志",
+                    "type": "string",
+                    "example": "9f1c2d3e4b5a69788796a5b4c3d2e1f0"
+                }
+            }
+        },
+        "http.HttpResponse-user_DeleteRes": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "code: HTTP 状态码",
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "description": "data: 响应数据（可以为空）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/user.DeleteRes"
+                        }
+                    ]
+                },
+                "message": {
+                    "description": "message: 响应描述",
+                    "type": "string",
+                    "example": "操作成功"
+                },
+                "request_id": {
+                    "description": "request_id: 请求 ID（仅失败响应返回，与响应头 X-Request-ID 一致），反馈问题时提供以便排查日志",
+                    "type": "string",
+                    "example": "9f1c2d3e4b5a69788796a5b4c3d2e1f0"
+                }
+            }
+        },
+        "http.HttpResponse-user_ResetPasswordRes": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "code: HTTP 状态码",
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "description": "data: 响应数据（可以为空）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/user.ResetPasswordRes"
+                        }
+                    ]
+                },
+                "message": {
+                    "description": "message: 响应描述",
+                    "type": "string",
+                    "example": "操作成功"
+                },
+                "request_id": {
+                    "description": "request_id: 请求 ID（仅失败响应返回，与响应头 X-Request-ID 一致），反馈问题时提供以便排查日志",
+                    "type": "string",
+                    "example": "9f1c2d3e4b5a69788796a5b4c3d2e1f0"
+                }
+            }
+        },
+        "http.HttpResponse-user_UpdateRes": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "code: HTTP 状态码",
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "description": "data: 响应数据（可以为空）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/user.UpdateRes"
+                        }
+                    ]
+                },
+                "message": {
+                    "description": "message: 响应描述",
+                    "type": "string",
+                    "example": "操作成功"
+                },
+                "request_id": {
+                    "description": "request_id: 请求 ID（仅失败响应返回，与响应头 X-Request-ID 一致），反馈问题时提供以便排查日志",
+                    "type": "string",
+                    "example": "9f1c2d3e4b5a69788796a5b4c3d2e1f0"
+                }
+            }
+        },
+        "http.HttpResponse-user_listRes": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "code: HTTP 状态码",
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "description": "data: 响应数据（可以为空）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/user.listRes"
+                        }
+                    ]
+                },
+                "message": {
+                    "description": "message: 响应描述",
+                    "type": "string",
+                    "example": "操作成功"
+                },
+                "request_id": {
+                    "description": "request_id: 请求 ID（仅失败响应返回，与响应头 X-Request-ID 一致），反馈问题时提供以便排查日志",
+                    "type": "string",
+                    "example": "9f1c2d3e4b5a69788796a5b4c3d2e1f0"
+                }
+            }
+        },
+        "http.PageRes-role_roleRes": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "description": "列表",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/role.roleRes"
+                    }
+                },
+                "page": {
+                    "description": "当前页码",
+                    "type": "integer",
+                    "example": 1
+                },
+                "size": {
+                    "description": "分页大小",
+                    "type": "integer",
+                    "example": 10
+                },
+                "total": {
+                    "description": "总数",
+                    "type": "integer",
+                    "example": 50
+                }
+            }
+        },
+        "http.PageRes-user_listRes": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "description": "列表",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/user.listRes"
+                    }
+                },
+                "page": {
+                    "description": "当前页码",
+                    "type": "integer",
+                    "example": 1
+                },
+                "size": {
+                    "description": "分页大小",
+                    "type": "integer",
+                    "example": 10
+                },
+                "total": {
+                    "description": "总数",
+                    "type": "integer",
+                    "example": 50
+                }
+            }
+        },
+        "http.PageRes-user_recycleRes": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "description": "列表",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/user.recycleRes"
+                    }
+                },
+                "page": {
+                    "description": "当前页码",
+                    "type": "integer",
+                    "example": 1
+                },
+                "size": {
+                    "description": "分页大小",
+                    "type": "integer",
+                    "example": 10
+                },
+                "total": {
+                    "description": "总数",
+                    "type": "integer",
+                    "example": 50
+                }
+            }
+        },
+        "menu.createReq": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "hidden": {
+                    "description": "是否在导航中隐藏",
+                    "type": "boolean"
+                },
+                "icon": {
+                    "description": "图标名称",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "users"
+                },
+                "name": {
+                    "description": "菜单名称",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "用户管理"
+                },
+                "parent_id": {
+                    "description": "父菜单 ID，0 表示顶级菜单",
+                    "type": "integer",
+                    "example": 0
+                },
+                "path": {
+                    "description": "前端路由路径，目录节点可为空",
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "/admin/users"
+                },
+                "permission": {
+                    "description": "绑定的权限点标识，为空表示登录即可见",
+                    "type": "string",
+                    "maxLength": 128,
+                    "example": "user:list"
+                },
+                "sort": {
+                    "description": "显示顺序",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "menu.menuRes": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "description": "* 子菜单",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/menu.menuRes"
+                    }
+                },
+                "hidden": {
+                    "description": "* 是否在导航中隐藏",
+                    "type": "boolean"
+                },
+                "icon": {
+                    "description": "* 图标名称",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "* 菜单 ID",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "* 菜单名称",
+                    "type": "string"
+                },
+                "parent_id": {
+                    "description": "* 父菜单 ID，0 表示顶级菜单",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "* 前端路由路径",
+                    "type": "string"
+                },
+                "permission": {
+                    "description": "* 绑定的权限点标识",
+                    "type": "string"
+                },
+                "sort": {
+                    "description": "* 显示顺序",
+                    "type": "integer"
+                }
+            }
+        },
+        "menu.updateReq": {
+            "type": "object",
+            "properties": {
+                "hidden": {
+                    "description": "是否在导航中隐藏",
+                    "type": "boolean"
+                },
+                "icon": {
+                    "description": "图标名称",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "name": {
+                    "description": "菜单名称",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "parent_id": {
+                    "description": "父菜单 ID，0 表示移动为顶级菜单",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "前端路由路径",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "permission": {
+                    "description": "绑定的权限点标识，传空字符串表示解除绑定",
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "sort": {
+                    "description": "显示顺序",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "role.createPermissionReq": {
+            "type": "object",
+            "required": [
+                "code",
+                "name"
+            ],
+            "properties": {
+                "code": {
+                    "description": "权限点标识，格式 \"资源:动作\"",
+                    "type": "string",
+                    "maxLength": 128,
+                    "example": "product:list"
+                },
+                "module": {
+                    "description": "所属模块",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "product"
+                },
+                "name": {
+                    "description": "权限点名称",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "商品列表"
+                },
+                "remark": {
+                    "description": "备注",
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "role.createReq": {
             "type": "object",
+            "required": [
+                "code",
+                "name"
+            ],
             "properties": {
-                "access_token": {
-                    "description": "访问令牌: 15分钟过期",
+                "code": {
+                    "description": "角色标识：小写字母、数字、下划线，创建后不可修改",
+                    "type": "string",
+                    "maxLength": 64,
+                    "minLength": 2,
+                    "example": "warehouse"
+                },
+                "name": {
+                    "description": "角色名称",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "仓库管理员"
+                },
+                "remark": {
+                    "description": "备注",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "require_mfa": {
+                    "description": "是否强制两步验证",
+                    "type": "boolean"
+                },
+                "sort": {
+                    "description": "显示顺序",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "role.permissionRes": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "* 权限点标识",
                     "type": "string"
                 },
-                "expires_at": {
-                    "description": "过期时间：访问令牌 Access_token 过期时间(秒)",
+                "module": {
+                    "description": "* 所属模块",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "* 权限点名称",
+                    "type": "string"
+                },
+                "remark": {
+                    "description": "* 备注",
+                    "type": "string"
+                }
+            }
+        },
+        "role.roleRes": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "* 角色标识",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "* 创建时间",
+                    "type": "string"
+                },
+                "is_active": {
+                    "description": "* 是否启用",
+                    "type": "boolean"
+                },
+                "is_builtin": {
+                    "description": "* 是否内置角色",
+                    "type": "boolean"
+                },
+                "name": {
+                    "description": "* 角色名称",
+                    "type": "string"
+                },
+                "remark": {
+                    "description": "* 备注",
+                    "type": "string"
+                },
+                "require_mfa": {
+                    "description": "* 是否强制两步验证",
+                    "type": "boolean"
+                },
+                "sort": {
+                    "description": "* 显示顺序",
                     "type": "integer"
                 },
-                "uid": {
-                    "description": "用户UID",
+                "updated_at": {
+                    "description": "* 更新时间",
                     "type": "string"
                 }
             }
         },
-        "auth.registerReq": {
+        "role.setPermissionsReq": {
             "type": "object",
             "required": [
-                "password",
-                "username"
+                "permissions"
             ],
             "properties": {
-                "password": {
-                    "description": "密码",
+                "permissions": {
+                    "description": "权限点标识列表，传空数组表示清空",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "user:list"
+                    ]
+                }
+            }
+        },
+        "role.updatePermissionReq": {
+            "type": "object",
+            "properties": {
+                "module": {
+                    "description": "所属模块",
                     "type": "string",
-                    "example": "123456"
+                    "maxLength": 64
                 },
-                "username": {
-                    "description": "用户名",
+                "name": {
+                    "description": "权限点名称",
                     "type": "string",
-                    "example": "admin"
+                    "maxLength": 64
+                },
+                "remark": {
+                    "description": "备注：使用指针区分 \"不修改\" 和 \"清空\"",
+                    "type": "string",
+                    "maxLength": 255
                 }
             }
         },
-        "http.Empty": {
-            "type": "object"
-        },
-        "http.HttpResponse-Empty": {
+        "role.updateReq": {
             "type": "object",
             "properties": {
-                "code": {
-                    "description": "code: HTTP 状态码",
-                    "type": "integer",
-                    "example": 200
+                "is_active": {
+                    "description": "使用指针，以便区分 \"不修改\" 和 \"修改为禁用(false)\"",
+                    "type": "boolean"
                 },
-                "data": {
-                    "description": "data: 响应数据（可以为空）",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/http.Empty"
-                        }
-                    ]
+                "name": {
+                    "description": "角色名称",
+                    "type": "string",
+                    "maxLength": 64
                 },
-                "message": {
-                    "description": "message: 响应描述",
+                "remark": {
+                    "description": "备注：使用指针区分 \"不修改\" 和 \"清空\"",
                     "type": "string",
-                    "example": "操作成功"
+                    "maxLength": 255
+                },
+                "require_mfa": {
+                    "description": "是否强制两步验证：使用指针区分 \"不修改\" 和 \"关闭\"",
+                    "type": "boolean"
+                },
+                "sort": {
+                    "description": "显示顺序：使用指针区分 \"不修改\" 和 \"修改为 0\"",
+                    "type": "integer",
+                    "minimum": 0
                 }
             }
         },
-        "http.HttpResponse-auth_loginRes": {
+        "system.componentLevelRes": {
             "type": "object",
             "properties": {
-                "code": {
-                    "description": "code: HTTP 状态码",
-                    "type": "integer",
-                    "example": 200
-                },
-                "data": {
-                    "description": "data: 响应数据（可以为空）",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/auth.loginRes"
-                        }
-                    ]
+                "level": {
+                    "description": "* 生效级别",
+                    "type": "string",
+                    "example": "warn"
                 },
-                "message": {
-                    "description": "message: 响应描述",
+                "name": {
+                    "description": "* 组件名称，与配置 log.levels 的 key 对应",
                     "type": "string",
-                    "example": "操作成功"
+                    "example": "gorm"
+                },
+                "overridden": {
+                    "description": "* 是否单独设置了级别，false 表示跟随全局默认级别",
+                    "type": "boolean"
                 }
             }
         },
-        "http.HttpResponse-http_PageRes-user_listRes": {
+        "system.logLevelRes": {
             "type": "object",
             "properties": {
-                "code": {
-                    "description": "code: HTTP 状态码",
-                    "type": "integer",
-                    "example": 200
+                "components": {
+                    "description": "* 各组件的生效级别",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/system.componentLevelRes"
+                    }
                 },
-                "data": {
-                    "description": "data: 响应数据（可以为空）",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/http.PageRes-user_listRes"
-                        }
-                    ]
+                "default": {
+                    "description": "* 全局默认级别",
+                    "type": "string",
+                    "example": "info"
+                }
+            }
+        },
+        "system.setLogLevelReq": {
+            "type": "object",
+            "properties": {
+                "component": {
+                    "description": "组件名称，为空或 default 表示全局默认级别",
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "gorm"
                 },
-                "message": {
-                    "description": "message: 响应描述",
+                "level": {
+                    "description": "日志级别：debug / info / warn / error；为空表示移除组件的单独设置，恢复跟随全局默认级别",
                     "type": "string",
-                    "example": "操作成功"
+                    "enum": [
+                        "debug",
+                        "info",
+                        "warn",
+                        "error"
+                    ],
+                    "example": "debug"
                 }
             }
         },
-        "http.PageRes-user_listRes": {
+        "user.CreateReq": {
             "type": "object",
+            "required": [
+                "password",
+                "roles",
+                "username"
+            ],
             "properties": {
-                "list": {
-                    "description": "列表",
+                "email": {
+                    "description": "选填，但如果有值必须符合邮箱格式",
+                    "type": "string"
+                },
+                "must_change_password": {
+                    "description": "选填，下次登录须修改密码（管理员代设初始密码时建议开启）",
+                    "type": "boolean"
+                },
+                "password": {
+                    "description": "必填，创建时传入明文密码，由 service 层按密码策略统一校验",
+                    "type": "string"
+                },
+                "roles": {
+                    "description": "角色标识列表：角色可在运行时新增，不要写死 oneof，由 service 层通过 role 模块统一校验",
                     "type": "array",
+                    "minItems": 1,
                     "items": {
-                        "$ref": "#/definitions/user.listRes"
+                        "type": "string"
                     }
                 },
-                "page": {
-                    "description": "当前页码",
-                    "type": "integer",
-                    "example": 1
+                "username": {
+                    "description": "必填，且通常有长度限制",
+                    "type": "string",
+                    "maxLength": 64,
+                    "minLength": 3
+                }
+            }
+        },
+        "user.CreateRes": {
+            "type": "object"
+        },
+        "user.DeleteRes": {
+            "type": "object"
+        },
+        "user.ResetPasswordReq": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "description": "选填，新密码（需满足密码策略）；不传时由服务端生成随机临时密码",
+                    "type": "string"
+                }
+            }
+        },
+        "user.ResetPasswordRes": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "description": "* 服务端生成的临时密码，仅返回一次（请求中指定了密码时为空）",
+                    "type": "string"
+                }
+            }
+        },
+        "user.UpdateReq": {
+            "type": "object",
+            "required": [
+                "roles"
+            ],
+            "properties": {
+                "email": {
+                    "description": "允许修改邮箱",
+                    "type": "string"
                 },
-                "size": {
-                    "description": "分页大小",
-                    "type": "integer",
-                    "example": 10
+                "is_active": {
+                    "description": "使用指针，以便区分 \"不修改\" 和 \"修改为禁用(false)\"",
+                    "type": "boolean"
                 },
-                "total": {
-                    "description": "总数",
-                    "type": "integer",
-                    "example": 50
+                "must_change_password": {
+                    "description": "下次登录须修改密码：使用指针区分 \"不修改\" 和 \"取消\"",
+                    "type": "boolean"
+                },
+                "roles": {
+                    "description": "允许修改角色（全量覆盖），不传或传空数组表示不修改；由 service 层通过 role 模块统一校验",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
+        "user.UpdateRes": {
+            "type": "object"
+        },
         "user.listRes": {
             "type": "object",
             "properties": {
@@ -405,10 +3094,65 @@ const docTemplate = `{
                     "description": "* 账号状态",
                     "type": "boolean"
                 },
-                "role": {
-                    "description": "* 角色标识",
+                "must_change_password": {
+                    "description": "* 下次登录须修改密码",
+                    "type": "boolean"
+                },
+                "roles": {
+                    "description": "* 角色标识列表",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "description": "* 更新时间",
+                    "type": "string"
+                },
+                "username": {
+                    "description": "* 登录用户名",
+                    "type": "string"
+                }
+            }
+        },
+        "user.recycleRes": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "* 创建时间 (JSON会自动格式化为 RFC3339 字符串)",
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "* 删除时间",
+                    "type": "string"
+                },
+                "email": {
+                    "description": "* 邮箱",
                     "type": "string"
                 },
+                "id": {
+                    "description": "* ID (对应数据库的 UID) - 前端通常习惯叫 id",
+                    "type": "string"
+                },
+                "is_active": {
+                    "description": "* 账号状态",
+                    "type": "boolean"
+                },
+                "must_change_password": {
+                    "description": "* 下次登录须修改密码",
+                    "type": "boolean"
+                },
+                "purge_at": {
+                    "description": "* 预计彻底删除时间（回收站不清理时为空）",
+                    "type": "string"
+                },
+                "roles": {
+                    "description": "* 角色标识列表",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "updated_at": {
                     "description": "* 更新时间",
                     "type": "string"
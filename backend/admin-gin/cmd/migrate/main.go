@@ -1,15 +1,57 @@
 // 数据库迁移
+//
+// 用法：go run ./cmd/migrate [-dir migrations] <command> [args]
+//
+//	up               执行全部未执行的迁移（默认命令）；内置角色、权限点与菜单由 cmd/seed 写入
+//	down [N]         按版本倒序回滚最近 N 个迁移（默认 1）
+//	status           查看各版本迁移的执行状态
+//	create <name>    在 -dir 目录下新建 up/down 迁移文件（无需连接数据库）
+//	force <version>  不执行 SQL，直接将版本记录设置为 version 并清除 dirty 标记（非事务迁移失败、人工修复后使用）
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"log/slog"
 	"mall-api/configs"
-	"mall-api/internal/app/admin/user"
 	"mall-api/internal/pkg/database"
+	"mall-api/internal/pkg/migrate"
+	"mall-api/migrations"
 	"os"
+	"strconv"
+	"text/tabwriter"
 )
 
 func main() {
+	dir := flag.String("dir", "migrations", "迁移文件目录（仅 create 使用，执行迁移时使用编译时嵌入的文件）")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "用法: migrate [-dir migrations] up | down [N] | status | create <name> | force <version>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	cmd, args := "up", flag.Args()
+	if len(args) > 0 {
+		cmd, args = args[0], args[1:]
+	}
+
+	// create 仅生成文件，不需要配置与数据库
+	if cmd == "create" {
+		if len(args) != 1 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		files, err := migrate.Create(*dir, args[0])
+		if err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+		for _, f := range files {
+			slog.Info("已创建迁移文件", "file", f)
+		}
+		return
+	}
 
 	// 1.从环境变量获取运行模式
 	runMode := os.Getenv("APP_ENV_MODE")
@@ -42,25 +84,101 @@ func main() {
 		os.Exit(1)
 	}
 
-	// 4. 获取底层的 sql.DB 对象用于关闭
+	// 4. 获取底层的 sql.DB 对象用于迁移与关闭
 	sqlDB, err := db.DB()
 	if err != nil {
 		slog.Error(err.Error())
+		os.Exit(1)
 	}
 
-	// 5. 使用 defer 确保在 main 函数结束时关闭连接
-	defer func() {
-		if err := sqlDB.Close(); err != nil {
-			slog.Error("关闭数据库连接失败：%v", "error", err.Error())
-		} else {
-			slog.Info("数据库连接已关闭")
-		}
-	}()
+	// 5. 加载编译时嵌入的迁移文件
+	list, err := migrate.Load(migrations.FS)
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+	m := migrate.New(sqlDB, list)
 
-	// 6. 迁移数据库
-	if err := db.AutoMigrate(&user.User{}); err != nil {
+	// 6. 执行子命令
+	ctx := context.Background()
+	err = run(ctx, m, cmd, args)
+
+	// 7. 关闭数据库连接（os.Exit 不会执行 defer，此处显式关闭）
+	if closeErr := sqlDB.Close(); closeErr != nil {
+		slog.Error("关闭数据库连接失败", "error", closeErr.Error())
+	}
+	if err != nil {
 		slog.Error(err.Error())
 		os.Exit(1)
 	}
-	slog.Info("数据库迁移成功")
+}
+
+func run(ctx context.Context, m *migrate.Migrator, cmd string, args []string) error {
+	switch cmd {
+	case "up":
+		n, err := m.Up(ctx)
+		if err != nil {
+			return err
+		}
+		slog.Info("数据库迁移成功", "applied", n)
+		return nil
+
+	case "down":
+		steps := 1
+		if len(args) > 0 {
+			v, err := strconv.Atoi(args[0])
+			if err != nil || v <= 0 {
+				return fmt.Errorf("回滚数量无效: %s", args[0])
+			}
+			steps = v
+		}
+		n, err := m.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		slog.Info("数据库回滚成功", "reverted", n)
+		return nil
+
+	case "status":
+		list, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, s := range list {
+			status, at := "pending", "-"
+			switch {
+			case s.Dirty:
+				status = "dirty"
+			case s.Missing:
+				status = "applied (missing file)"
+			case s.Applied:
+				status = "applied"
+			}
+			if s.AppliedAt != nil {
+				at = s.AppliedAt.Local().Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%06d\t%s\t%s\t%s\n", s.Version, s.Name, status, at)
+		}
+		return w.Flush()
+
+	case "force":
+		if len(args) != 1 {
+			return fmt.Errorf("用法: migrate force <version>")
+		}
+		v, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil || v < 0 {
+			return fmt.Errorf("迁移版本无效: %s", args[0])
+		}
+		if err := m.Force(ctx, v); err != nil {
+			return err
+		}
+		slog.Info("迁移版本已强制设置", "version", v)
+		return nil
+
+	default:
+		flag.Usage()
+		return fmt.Errorf("未知命令: %s", cmd)
+	}
 }
@@ -0,0 +1,129 @@
+// 初始化数据（幂等，可重复执行；需先执行 cmd/migrate up）
+//
+// 用法：go run ./cmd/seed [-username admin] [-email admin@example.com] [-password xxx]
+//
+//  1. 写入内置权限点、内置角色与默认菜单（已存在的数据不会被修改，新增的内置权限点按默认定义绑定到内置角色）
+//  2. 不存在可用的超级管理员时创建首个超级管理员：
+//     参数未指定时读取环境变量 SEED_ADMIN_USERNAME / SEED_ADMIN_EMAIL / SEED_ADMIN_PASSWORD；
+//     未提供密码时按密码策略生成临时密码并输出一次，首次登录须修改
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"mall-api/configs"
+	"mall-api/internal/app/admin/iam/menu"
+	"mall-api/internal/app/admin/iam/role"
+	"mall-api/internal/app/admin/identity"
+	"mall-api/internal/app/admin/user"
+	"mall-api/internal/boot"
+	"mall-api/internal/pkg/database"
+	"os"
+
+	"gorm.io/gorm"
+)
+
+func main() {
+	username := flag.String("username", envOr("SEED_ADMIN_USERNAME", "admin"), "超级管理员用户名（环境变量 SEED_ADMIN_USERNAME）")
+	email := flag.String("email", os.Getenv("SEED_ADMIN_EMAIL"), "超级管理员邮箱，用于找回密码（环境变量 SEED_ADMIN_EMAIL）")
+	pwd := flag.String("password", os.Getenv("SEED_ADMIN_PASSWORD"), "超级管理员密码，留空自动生成（建议使用环境变量 SEED_ADMIN_PASSWORD，避免出现在进程列表中）")
+	flag.Parse()
+
+	// 仅在此处退出：run 返回前已执行其中 defer 的数据库 / redis 关闭
+	if err := run(user.SuperAdmin{Username: *username, Email: *email, Password: *pwd}); err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+// run 连接数据库与 redis 后写入初始化数据
+func run(admin user.SuperAdmin) error {
+	// 1.从环境变量获取运行模式
+	runMode := os.Getenv("APP_ENV_MODE")
+	env := "dev"
+	if runMode != "" {
+		env = runMode
+	}
+
+	// 2. 依据环境变量初始化系统配置
+	cfg, err := configs.InitConfig(env)
+	if err != nil {
+		return err
+	}
+
+	// 3.连接数据库与 redis（分配角色时需失效用户权限缓存）
+	db, err := database.NewPostgre(&database.PostgreConfig{
+		Host:            cfg.Database.Host,
+		User:            cfg.Database.User,
+		Password:        cfg.Database.Password,
+		DBName:          cfg.Database.DBName,
+		Port:            cfg.Database.Port,
+		TimeZone:        cfg.Database.TimeZone,
+		MaxIdleConns:    cfg.Database.MaxIdleConns,
+		MaxOpenConns:    cfg.Database.MaxOpenConns,
+		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+	})
+	if err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	rdb, err := database.NewRedis(&database.RedisConfig{
+		Addr:         cfg.Redis.Addr,
+		Password:     cfg.Redis.Password,
+		DB:           cfg.Redis.DB,
+		DialTimeout:  cfg.Redis.DialTimeout,
+		ReadTimeout:  cfg.Redis.ReadTimeout,
+		WriteTimeout: cfg.Redis.WriteTimeout,
+	})
+	if err != nil {
+		return err
+	}
+	defer rdb.Close()
+
+	ctx := context.Background()
+
+	// 4. 参考数据：内置权限点、内置角色、默认菜单
+	if err := seedReference(ctx, db); err != nil {
+		return err
+	}
+	slog.Info("内置角色、权限点与默认菜单已就绪")
+
+	// 5. 首个超级管理员
+	res, err := user.SeedSuperAdmin(ctx, identity.NewStore(db), role.NewUserRoles(db, rdb), boot.PasswordPolicy(&cfg.Auth.Password), admin)
+	if err != nil {
+		return fmt.Errorf("创建超级管理员 %s 失败: %w", admin.Username, err)
+	}
+
+	switch {
+	case !res.Created:
+		slog.Info("已存在可用的超级管理员，跳过创建")
+	case res.Password != "":
+		slog.Info("超级管理员已创建，首次登录须修改密码并绑定两步验证", "username", admin.Username)
+		fmt.Printf("\n  用户名: %s\n  临时密码: %s\n\n  临时密码仅显示一次，请妥善保存\n\n", admin.Username, res.Password)
+	default:
+		slog.Info("超级管理员已创建，首次登录须绑定两步验证", "username", admin.Username)
+	}
+	return nil
+}
+
+// seedReference 写入内置权限点、内置角色与默认菜单
+func seedReference(ctx context.Context, db *gorm.DB) error {
+	if err := role.SeedBuiltin(ctx, db); err != nil {
+		return err
+	}
+	return menu.SeedBuiltin(ctx, db)
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
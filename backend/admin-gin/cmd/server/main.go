@@ -59,11 +59,14 @@ func main() {
 	}
 
 	// 4.注入依赖
-	boot.Register(app.Ge, app.Db, app.Rdb, app.Jt, app.Cm)
+	boot.Register(app.Ge, app.Db, app.Rdb, app.Jt, app.Cm, app.Ml, app.Lc, app.Hc, cfg)
 
 	// 5. 端口打印
 	fmt.Printf("【%s】service is running on port: %d \n\n", strings.ToUpper(cfg.App.Name), cfg.Server.Port)
 
-	// 6. 运行 http 服务
-	app.Se.ListenAndServe()
+	// 6. 运行 http 服务与后台任务，收到 SIGINT / SIGTERM 后优雅停机
+	if err := app.Run(); err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
 }
@@ -15,8 +15,13 @@ type Config struct {
 	Database Database `mapstructure:"database"`
 	Redis    Redis    `mapstructure:"redis"`
 	JWT      JWT      `mapstructure:"jwt"`
+	Auth     Auth     `mapstructure:"auth"`
+	Mail     Mail     `mapstructure:"mail"`
+	User     User     `mapstructure:"user"`
 	Log      Log      `mapstructure:"log"`
 	CORS     CORS     `mapstructure:"cors"`
+	Metrics  Metrics  `mapstructure:"metrics"`
+	Tracing  Tracing  `mapstructure:"tracing"`
 }
 
 // App 应用基础配置
@@ -31,6 +36,13 @@ type Server struct {
 	Mode         string `mapstructure:"mode"`          // debug, release, test
 	ReadTimeout  int    `mapstructure:"read_timeout"`  // 秒
 	WriteTimeout int    `mapstructure:"write_timeout"` // 秒
+
+	ShutdownTimeout int `mapstructure:"shutdown_timeout"` // 优雅停机等待时间(秒)：等待进行中的请求与后台任务退出，0 时取 15 秒
+	ShutdownDelay   int `mapstructure:"shutdown_delay"`   // 停机时 /readyz 置为未就绪后、停止接受新请求前的等待时间(秒)，供负载均衡摘除流量，计入 shutdown_timeout
+
+	HealthCheckTimeout int `mapstructure:"health_check_timeout"` // /readyz 单项依赖检查超时(秒)，0 时取 2 秒
+
+	TrustedProxies []string `mapstructure:"trusted_proxies"` // 可信反向代理的 IP / CIDR：仅这些来源的 X-Forwarded-For 用于解析客户端 IP，为空时不信任任何代理
 }
 
 // Database 数据库配置 (PostgreSQL)
@@ -60,21 +72,108 @@ type Redis struct {
 
 // JWT 认证配置 (双Token)
 type JWT struct {
-	Secret        string `mapstructure:"secret"`
+	Secret        string `mapstructure:"secret"` // 未配置 keys 时使用 HS256 + secret（兼容旧配置）
 	Issuer        string `mapstructure:"issuer"`
 	AccessExpire  int64  `mapstructure:"access_expire"`  // 秒
 	RefreshExpire int64  `mapstructure:"refresh_expire"` // 秒
+
+	ActiveKID      string   `mapstructure:"active_kid"`       // 当前签名密钥的 kid
+	KeyGracePeriod int64    `mapstructure:"key_grace_period"` // 退役密钥继续验签的宽限期(秒)，0 表示取 refresh_expire
+	Keys           []JWTKey `mapstructure:"keys"`             // 签名密钥列表（支持轮换）
+}
+
+// JWTKey JWT 签名密钥
+type JWTKey struct {
+	KID            string `mapstructure:"kid"`
+	Algorithm      string `mapstructure:"algorithm"`        // HS256 / RS256 / EdDSA
+	Secret         string `mapstructure:"secret"`           // HS256 共享密钥
+	PrivateKeyFile string `mapstructure:"private_key_file"` // RS256 / EdDSA 私钥 PEM 文件
+	PublicKeyFile  string `mapstructure:"public_key_file"`  // RS256 / EdDSA 公钥 PEM 文件（可选，默认由私钥推导）
+	RetiredAt      string `mapstructure:"retired_at"`       // 退役时间(RFC3339)，为空表示未退役
+}
+
+// Auth 认证策略配置
+type Auth struct {
+	MaxSessions int `mapstructure:"max_sessions"` // 单用户最大同时在线会话数，0 表示不限制；超出时踢下最久未活跃的会话
+
+	AllowRegister bool `mapstructure:"allow_register"` // 是否开放 /admin/auth/register 公开注册，默认关闭；生产环境必须关闭，首个超级管理员由 cmd/seed 创建
+
+	// --- 登录防暴力破解 ---
+	LoginMaxFailures   int   `mapstructure:"login_max_failures"`    // 同一用户名在窗口期内失败次数达到后锁定，0 表示不限制
+	LoginIPMaxFailures int   `mapstructure:"login_ip_max_failures"` // 同一 IP 在窗口期内失败次数达到后锁定，0 表示不限制
+	LoginFailureWindow int64 `mapstructure:"login_failure_window"`  // 失败计数窗口(秒)
+	LoginLockDuration  int64 `mapstructure:"login_lock_duration"`   // 锁定时长(秒)
+	LoginDelayStep     int64 `mapstructure:"login_delay_step"`      // 渐进延迟步长(毫秒)：已失败 n 次时下一次登录延迟 n*step，0 表示不延迟
+
+	Password Password `mapstructure:"password"` // 密码策略
+
+	// --- 找回密码 ---
+	PasswordResetTTL int64  `mapstructure:"password_reset_ttl"` // 重置链接有效期(秒)，0 时取 30 分钟
+	PasswordResetURL string `mapstructure:"password_reset_url"` // 前端重置密码页地址，邮件中的链接为该地址附加 ?token=xxx
+}
+
+// Password 密码策略配置：注册、创建用户、修改密码时统一校验
+type Password struct {
+	MinLength     int  `mapstructure:"min_length"`     // 最小长度，0 时取 8
+	MaxLength     int  `mapstructure:"max_length"`     // 最大长度(字节)，0 或超过 72 时取 72（bcrypt 上限）
+	RequireUpper  bool `mapstructure:"require_upper"`  // 必须包含大写字母
+	RequireLower  bool `mapstructure:"require_lower"`  // 必须包含小写字母
+	RequireDigit  bool `mapstructure:"require_digit"`  // 必须包含数字
+	RequireSymbol bool `mapstructure:"require_symbol"` // 必须包含特殊字符
+	History       int  `mapstructure:"history"`        // 不可与最近 N 次使用过的密码相同，0 时取 5，-1 表示不限制
+}
+
+// User 后台用户管理配置
+type User struct {
+	RecycleRetention int64 `mapstructure:"recycle_retention"` // 回收站保留期(秒)：删除超过该时长的用户被彻底删除，0 时取 30 天，-1 表示不清理
+	PurgeInterval    int64 `mapstructure:"purge_interval"`    // 回收站清理任务执行间隔(秒)，0 时取 1 小时
+}
+
+// Mail 邮件发送配置
+type Mail struct {
+	Driver string   `mapstructure:"driver"` // smtp / log（本地开发：仅输出日志，不实际发送）
+	From   string   `mapstructure:"from"`   // 发件人，如 "Mall Admin <no-reply@example.com>"
+	Dir    string   `mapstructure:"dir"`    // log 驱动：邮件另存为 .eml 文件的目录，留空仅输出日志
+	SMTP   MailSMTP `mapstructure:"smtp"`
+}
+
+// MailSMTP SMTP 服务器配置
+type MailSMTP struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"` // 为空时不进行认证
+	Password string `mapstructure:"password"`
+	TLS      string `mapstructure:"tls"` // starttls(默认，587) / tls(465) / none
 }
 
 // Log 日志配置
 type Log struct {
-	Level    string `mapstructure:"level"`    // debug, info, warn, error
+	Level    string `mapstructure:"level"`    // debug, info, warn, error；拼写错误时启动失败
 	Format   string `mapstructure:"format"`   // json, text
 	Dir      string `mapstructure:"dir"`      // 日志文件夹
 	Filename string `mapstructure:"filename"` // 日志文件名
 	MaxSize  int    `mapstructure:"max_size"` // MB
 	MaxAge   int    `mapstructure:"max_age"`  // 天
 	Compress bool   `mapstructure:"compress"` // 是否压缩
+
+	Levels        map[string]string `mapstructure:"levels"`         // 组件级别，覆盖 level，如 gorm: warn、auth: debug；运行时可通过 /admin/system/log-level 修改
+	SlowThreshold int               `mapstructure:"slow_threshold"` // 慢查询阈值(毫秒)：超过时以 warn 级别输出 SQL，0 时取 200
+
+	Redact LogRedact `mapstructure:"redact"`
+	Body   LogBody   `mapstructure:"body"`
+}
+
+// LogRedact 日志脱敏：所有日志输出前按字段名 / 请求头替换为 ***，并对字符串按模式打码
+type LogRedact struct {
+	Fields   []string `mapstructure:"fields"`   // 追加脱敏的字段名（默认已包含 password、token、secret 等）
+	Headers  []string `mapstructure:"headers"`  // 追加脱敏的请求 / 响应头（默认已包含 Authorization、Cookie、Set-Cookie 等）
+	Patterns []string `mapstructure:"patterns"` // 打码模式：token / email / phone，为空时全部启用
+}
+
+// LogBody 请求 / 响应体日志（经脱敏输出），默认关闭
+type LogBody struct {
+	Enabled bool `mapstructure:"enabled"`
+	MaxSize int  `mapstructure:"max_size"` // 记录上限(字节)：超过时不记录内容，0 时取 4096
 }
 
 // CORS 跨域配置
@@ -82,6 +181,20 @@ type CORS struct {
 	AllowOrigins []string `mapstructure:"allow_origins"`
 }
 
+// Metrics Prometheus 指标配置
+type Metrics struct {
+	Enabled bool   `mapstructure:"enabled"` // 是否采集并暴露指标（HTTP、数据库、redis、业务计数）
+	Path    string `mapstructure:"path"`    // 指标路径，为空时取 /metrics
+	Token   string `mapstructure:"token"`   // 非空时抓取须携带 Authorization: Bearer <token>
+}
+
+// Tracing OpenTelemetry 链路追踪配置
+type Tracing struct {
+	Exporter    string  `mapstructure:"exporter"`     // otlp / stdout / none（默认：不导出，仅生成 trace_id 用于日志关联）
+	Endpoint    string  `mapstructure:"endpoint"`     // otlp：OTLP/HTTP 地址，如 http://localhost:4318；为空时读取 OTEL_EXPORTER_OTLP_ENDPOINT
+	SampleRatio float64 `mapstructure:"sample_ratio"` // 根 span 采样率 (0,1]，0 时取 1；请求携带 traceparent 时遵循上游的采样决定
+}
+
 // ================================ viper 配置项目初始化 ===================================
 
 func InitConfig(env string) (*Config, error) {
@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"time"
+
+	"mall-api/internal/pkg/password"
+)
+
+// Config auth 模块策略配置（由 boot 从 configs.Auth 转换后注入）
+type Config struct {
+	MaxSessions int // 单用户最大同时在线会话数，0 表示不限制
+
+	AllowRegister bool // 是否开放公开注册（注册用户获得 admin 角色），生产环境应关闭
+
+	LoginMaxFailures   int           // 同一用户名在窗口期内失败次数达到后锁定，0 表示不限制
+	LoginIPMaxFailures int           // 同一 IP 在窗口期内失败次数达到后锁定，0 表示不限制
+	LoginFailureWindow time.Duration // 失败计数窗口，0 时取 defaultLoginWindow
+	LoginLockDuration  time.Duration // 锁定时长，0 时取 defaultLoginWindow
+	LoginDelayStep     time.Duration // 渐进延迟步长，0 表示不延迟
+
+	MFAIssuer string // TOTP 签发方名称，显示在身份验证器 App 中
+
+	PasswordPolicy password.Policy // 密码策略：注册、修改密码时校验
+
+	PasswordResetTTL time.Duration // 找回密码重置链接有效期，0 时取 defaultPasswordResetTTL
+	PasswordResetURL string        // 前端重置密码页地址，邮件链接为该地址附加 token 参数
+
+	AppName string // 应用名称，显示在邮件主题与落款中
+}
+
+const (
+	defaultLoginWindow = 15 * time.Minute
+	maxLoginDelay      = 5 * time.Second // 渐进延迟上限
+
+	mfaChallengeTTL      = 5 * time.Minute // 两步验证挑战令牌有效期
+	mfaChallengeAttempts = 5               // 单个挑战令牌允许的验证码错误次数
+	mfaRecoveryCodeCount = 10              // 恢复码数量
+	defaultMFAIssuer     = "mall-admin"
+
+	defaultPasswordResetTTL   = 30 * time.Minute
+	passwordResetMailInterval = time.Minute // 同一用户发送重置邮件的最小间隔
+)
@@ -1,5 +1,7 @@
 package auth
 
+import "time"
+
 type loginReq struct {
 	Username string `json:"username" binding:"required" example:"admin"`  // 用户名
 	Password string `json:"password" binding:"required" example:"123456"` // 密码
@@ -10,8 +12,78 @@ type loginRes struct {
 	AccessToken  string `json:"access_token"`           // 访问令牌: 15分钟过期
 	ExpiresAt    int64  `json:"expires_at"`             // 过期时间：访问令牌 Access_token 过期时间(秒)
 	RefreshToken string `json:"-" swaggerignore:"true"` // 刷新令牌不返回前端,JSON 转换也不转换该字段，该字段只在/admin/auth/refresh 接口cookie中携带，还需要配置必要的安全设置
+
+	MustChangePassword bool `json:"must_change_password,omitempty"` // 须修改密码：为 true 时除修改密码、注销外的接口均返回 403，前端应跳转修改密码页
 }
 
 type registerReq struct {
 	loginReq
 }
+
+type unlockReq struct {
+	Username string `json:"username" binding:"required" example:"admin"`   // 被锁定的用户名
+	IP       string `json:"ip" binding:"omitempty,ip" example:"127.0.0.1"` // 可选：同时解除该 IP 的锁定
+}
+
+type sessionRes struct {
+	SID        string    `json:"sid"`          // 会话标识
+	Device     string    `json:"device"`       // 设备：由 User-Agent 识别，如 "Chrome / macOS"
+	IP         string    `json:"ip"`           // 最近一次登录/刷新的 IP
+	UserAgent  string    `json:"user_agent"`   // 登录时的 User-Agent
+	CreatedAt  time.Time `json:"created_at"`   // 登录时间
+	LastSeenAt time.Time `json:"last_seen_at"` // 最近活跃时间（登录、刷新令牌时更新）
+	Current    bool      `json:"current"`      // 是否为当前请求所属会话
+}
+
+type mfaChallengeRes struct {
+	MFAToken      string `json:"mfa_token"`      // 两步验证挑战令牌：5 分钟内有效，用于 /admin/auth/login/mfa
+	SetupRequired bool   `json:"setup_required"` // 所属角色要求两步验证但尚未绑定，需先调用 /admin/auth/login/mfa/setup 绑定
+}
+
+type mfaTokenReq struct {
+	MFAToken string `json:"mfa_token" binding:"required"` // 登录返回的两步验证挑战令牌
+}
+
+type mfaLoginReq struct {
+	MFAToken string `json:"mfa_token" binding:"required"`                    // 登录返回的两步验证挑战令牌
+	Code     string `json:"code" binding:"required,max=32" example:"123456"` // 6 位 TOTP 验证码或恢复码（强制绑定时只能使用 TOTP 验证码）
+}
+
+type mfaLoginRes struct {
+	loginRes
+	RecoveryCodes []string `json:"recovery_codes,omitempty"` // 强制绑定时返回的恢复码，仅展示一次
+}
+
+type mfaCodeReq struct {
+	Code string `json:"code" binding:"required,max=32" example:"123456"` // 6 位 TOTP 验证码或恢复码
+}
+
+type mfaSetupRes struct {
+	Secret     string `json:"secret"`      // TOTP 密钥（Base32），无法扫码时手动输入
+	OTPAuthURL string `json:"otpauth_url"` // otpauth:// URI，前端生成二维码供身份验证器 App 扫描
+}
+
+type mfaStatusRes struct {
+	Enabled       bool `json:"enabled"`        // 是否已启用两步验证
+	Required      bool `json:"required"`       // 所属角色是否强制两步验证（强制时不可关闭）
+	RecoveryCodes int  `json:"recovery_codes"` // 剩余可用恢复码数量
+}
+
+type recoveryCodesRes struct {
+	RecoveryCodes []string `json:"recovery_codes"` // 恢复码：每个仅可使用一次，仅展示一次，请妥善保存
+}
+
+type forgotPasswordReq struct {
+	Email string `json:"email" binding:"required,email,max=128" example:"admin@example.com"` // 账号绑定的邮箱
+	Lang  string `json:"lang" binding:"omitempty,max=16" example:"zh"`                       // 邮件语言：zh / en，留空时按 Accept-Language 选择
+}
+
+type resetPasswordReq struct {
+	Token       string `json:"token" binding:"required,max=128"` // 重置邮件链接中的 token 参数
+	NewPassword string `json:"new_password" binding:"required"`  // 新密码：需满足密码策略，且不能与最近使用过的密码相同
+}
+
+type changePasswordReq struct {
+	OldPassword string `json:"old_password" binding:"required"` // 原密码
+	NewPassword string `json:"new_password" binding:"required"` // 新密码：需满足密码策略，且不能与最近使用过的密码相同
+}
@@ -1,14 +1,16 @@
 package auth
 
 import (
-	"net/http"
-
 	"mall-api/internal/pkg/cookie"
+	"mall-api/internal/pkg/errs"
 	pkghttp "mall-api/internal/pkg/http"
 
 	"github.com/gin-gonic/gin"
 )
 
+// errRefreshCookieMissing 请求未携带 refresh_token cookie
+var errRefreshCookieMissing = errs.BadRequest("缺少 refresh_token")
+
 type handler struct {
 	se service
 	cm *cookie.CookieManager
@@ -19,7 +21,9 @@ func newHandler(se service, cm *cookie.CookieManager) *handler {
 }
 
 // @Summary		用户登录
-// @Description	用户名/密码登录
+// @Description	用户名/密码登录；用户不存在与密码错误统一返回 401，失败次数过多时用户名 / IP 被临时锁定并返回 429；账号已禁用 / 删除返回 403
+// @Description	已启用两步验证，或所属角色强制两步验证时，data 为 mfaChallengeRes（mfa_token / setup_required）而非 token 对，需调用 /admin/auth/login/mfa 完成登录
+// @Description	管理员要求下次登录修改密码时，返回 must_change_password=true，此时除修改密码、注销外的接口均返回 403
 // @ID				login
 // @Tags			Auth
 // @Accept			json
@@ -32,25 +36,86 @@ func (h *handler) login(c *gin.Context) {
 	// 1.读取接口传参
 	var req loginReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		pkghttp.Fail(c, http.StatusBadRequest)
+		c.Error(pkghttp.BindError(err))
 		return
 	}
 
 	// 2.调用 service 层的 login 业务
-	res, err := h.se.login(c.Request.Context(), &req)
+	res, challenge, err := h.se.login(c.Request.Context(), &req, clientOf(c))
+	observeLogin(loginStepPassword, challenge != nil, err)
 	if err != nil {
-		pkghttp.Fail(c, http.StatusUnauthorized, err.Error())
+		c.Error(err)
 		return
 	}
 
-	// 3. gin 设置 refresh cookie
+	// 3. 需要两步验证：返回挑战令牌，不签发 token
+	if challenge != nil {
+		pkghttp.OK(c, challenge)
+		return
+	}
+
+	// 4. gin 设置 refresh cookie
 	h.cm.Set(c, res.RefreshToken)
 
 	pkghttp.OK(c, res)
 }
 
+// @Summary		两步验证登录
+// @Description	凭登录返回的 mfa_token 提交 6 位 TOTP 验证码或恢复码完成登录；强制绑定（setup_required）时提交绑定后的 TOTP 验证码，响应额外返回恢复码
+// @Description	验证码错误计入登录失败次数，同一 mfa_token 错误 5 次后失效
+// @ID				loginMFA
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			data	body		mfaLoginReq							true	"验证参数"
+// @Success		200		{object}	pkghttp.HttpResponse[mfaLoginRes]	"登录成功"
+// @Router			/admin/auth/login/mfa [post]
+func (h *handler) loginMFA(c *gin.Context) {
+	var req mfaLoginReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkghttp.BindError(err))
+		return
+	}
+
+	res, err := h.se.loginMFA(c.Request.Context(), &req, clientOf(c))
+	observeLogin(loginStepMFA, false, err)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.cm.Set(c, res.RefreshToken)
+
+	pkghttp.OK(c, res)
+}
+
+// @Summary		登录时绑定两步验证
+// @Description	所属角色强制两步验证但尚未绑定时，凭 mfa_token 生成 TOTP 密钥；在身份验证器 App 中添加后调用 /admin/auth/login/mfa 完成绑定与登录
+// @ID				loginMFASetup
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			data	body		mfaTokenReq							true	"挑战令牌"
+// @Success		200		{object}	pkghttp.HttpResponse[mfaSetupRes]	"生成成功"
+// @Router			/admin/auth/login/mfa/setup [post]
+func (h *handler) loginMFASetup(c *gin.Context) {
+	var req mfaTokenReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkghttp.BindError(err))
+		return
+	}
+
+	res, err := h.se.loginMFASetup(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, res)
+}
+
 // @Summary		用户注册
-// @Description	用户名/密码进行注册
+// @Description	用户名/密码进行注册；未开放注册（auth.allow_register=false）时返回 403，密码不满足密码策略时返回 422
 // @ID				register
 // @Tags			Auth
 // @Accept			json
@@ -63,13 +128,13 @@ func (h *handler) register(c *gin.Context) {
 	// 1. 读取接口传参
 	var req registerReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		pkghttp.Fail(c, http.StatusBadRequest)
+		c.Error(pkghttp.BindError(err))
 		return
 	}
 
 	// 2. 调用 service 层的用户注册
-	if err := h.se.register(&req); err != nil {
-		pkghttp.Fail(c, http.StatusConflict, err.Error())
+	if err := h.se.register(c.Request.Context(), &req); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -93,14 +158,13 @@ func (h *handler) logout(c *gin.Context) {
 	// 1.参数 refresh_token, 从 cookie 中取值
 	refreshToken, err := h.cm.Get(c)
 	if err != nil {
-		pkghttp.Fail(c, http.StatusBadRequest)
+		c.Error(errRefreshCookieMissing)
 		return
 	}
 
 	// 2. 调用 service 层注销业务
-	code, err := h.se.logout(c.Request.Context(), refreshToken)
-	if err != nil {
-		pkghttp.Fail(c, code, err.Error())
+	if err := h.se.logout(c.Request.Context(), refreshToken); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -113,7 +177,7 @@ func (h *handler) logout(c *gin.Context) {
 }
 
 // @Summary		刷新令牌
-// @Description	用于短期令牌 Access_Token 续期
+// @Description	用于短期令牌 Access_Token 续期；每次刷新都会轮换 refresh_token cookie，重复使用已轮换的 refresh_token 将导致该会话被下线
 // @ID				refreshToken
 // @Security		CookieAuth
 // @Tags			Auth
@@ -127,17 +191,308 @@ func (h *handler) refresh(c *gin.Context) {
 	// 1.参数 refresh_token, 从 cookie 中取值
 	refreshToken, err := h.cm.Get(c)
 	if err != nil {
-		pkghttp.Fail(c, http.StatusBadRequest)
+		c.Error(errRefreshCookieMissing)
 		return
 	}
 
 	// 2.调用 service 层的 refresh 业务
-	res, err := h.se.refresh(c.Request.Context(), refreshToken)
+	res, err := h.se.refresh(c.Request.Context(), refreshToken, clientOf(c))
 	if err != nil {
-		pkghttp.Fail(c, http.StatusInternalServerError, err.Error())
+		if errs.KindOf(err) == errs.KindUnauthorized {
+			h.cm.Remove(c) // 会话已失效，清除无用的 cookie
+		}
+		c.Error(err)
 		return
 	}
 
-	// 3.返回 token 对
+	// 3. 轮换 refresh cookie：旧 token 自此作废
+	h.cm.Set(c, res.RefreshToken)
+
+	// 4.返回 token 对
 	pkghttp.OK(c, res)
 }
+
+// @Summary		修改密码
+// @Description	校验原密码后修改密码；新密码需满足密码策略且不能与最近使用过的密码相同（422），原密码错误返回 400 并计入登录失败次数
+// @Description	修改成功后解除 "须修改密码" 限制，并下线当前会话以外的全部会话
+// @Security		BearerAuth
+// @ID				changePassword
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			data	body		changePasswordReq			true	"修改密码参数"
+// @Success		200		{object}	pkghttp.HttpResponse[Empty]	"修改成功"
+// @Router			/admin/auth/password [put]
+func (h *handler) changePassword(c *gin.Context) {
+	var req changePasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkghttp.BindError(err))
+		return
+	}
+
+	err := h.se.changePassword(c.Request.Context(), c.GetString("uid"), c.GetString("sid"), &req, clientOf(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, pkghttp.Empty{})
+}
+
+// @Summary		找回密码
+// @Description	向邮箱发送重置密码链接（默认 30 分钟内有效，仅可使用一次，再次申请时旧链接作废）
+// @Description	无论邮箱是否存在、账号是否可用均返回成功，避免枚举邮箱；同一账号 1 分钟内只发送一次
+// @ID				forgotPassword
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			Accept-Language	header		string						false	"邮件语言（请求参数 lang 为空时生效）"
+// @Param			data			body		forgotPasswordReq			true	"找回密码参数"
+// @Success		200				{object}	pkghttp.HttpResponse[Empty]	"如邮箱已绑定账号，将收到重置邮件"
+// @Router			/admin/auth/password/forgot [post]
+func (h *handler) forgotPassword(c *gin.Context) {
+	var req forgotPasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkghttp.BindError(err))
+		return
+	}
+
+	lang := mailLang(req.Lang, c.GetHeader("Accept-Language"))
+	if err := h.se.forgotPassword(c.Request.Context(), &req, lang); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, pkghttp.Empty{})
+}
+
+// @Summary		重置密码
+// @Description	凭重置邮件中的令牌设置新密码；令牌无效、已过期或已使用返回 400，新密码不满足密码策略返回 422（令牌仍可继续使用）
+// @Description	重置成功后解除 "须修改密码" 限制与登录锁定，并下线该用户的全部会话
+// @ID				resetPassword
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			data	body		resetPasswordReq			true	"重置密码参数"
+// @Success		200		{object}	pkghttp.HttpResponse[Empty]	"重置成功"
+// @Router			/admin/auth/password/reset [post]
+func (h *handler) resetPassword(c *gin.Context) {
+	var req resetPasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkghttp.BindError(err))
+		return
+	}
+
+	if err := h.se.resetPassword(c.Request.Context(), &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, pkghttp.Empty{})
+}
+
+// @Summary		解除登录锁定
+// @Description	清除用户名（以及可选的 IP）的登录失败计数与锁定
+// @Security		BearerAuth
+// @ID				unlockLogin
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			data	body		unlockReq					true	"解锁参数"
+// @Success		200		{object}	pkghttp.HttpResponse[Empty]	"解锁成功"
+// @Router			/admin/auth/unlock [post]
+func (h *handler) unlock(c *gin.Context) {
+	var req unlockReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkghttp.BindError(err))
+		return
+	}
+
+	if err := h.se.unlock(c.Request.Context(), &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, pkghttp.Empty{})
+}
+
+// @Summary		在线会话列表
+// @Description	查询当前用户在各设备上的登录会话，current 标记当前会话
+// @Security		BearerAuth
+// @ID				listSession
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Success		200	{object}	pkghttp.HttpResponse[[]sessionRes]	"查询成功"
+// @Router			/admin/auth/session [get]
+func (h *handler) listSessions(c *gin.Context) {
+	res, err := h.se.listSessions(c.Request.Context(), c.GetString("uid"), c.GetString("sid"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, res)
+}
+
+// @Summary		下线指定会话
+// @Description	使指定会话的刷新令牌失效，该设备需重新登录
+// @Security		BearerAuth
+// @ID				revokeSession
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			sid	path		string						true	"会话标识"
+// @Success		200	{object}	pkghttp.HttpResponse[Empty]	"下线成功"
+// @Router			/admin/auth/session/{sid} [delete]
+func (h *handler) revokeSession(c *gin.Context) {
+	if err := h.se.revokeSession(c.Request.Context(), c.GetString("uid"), c.Param("sid")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, pkghttp.Empty{})
+}
+
+// @Summary		下线其他会话
+// @Description	保留当前会话，使其他设备上的会话全部失效
+// @Security		BearerAuth
+// @ID				revokeOtherSessions
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Success		200	{object}	pkghttp.HttpResponse[Empty]	"下线成功"
+// @Router			/admin/auth/session/others [delete]
+func (h *handler) revokeOtherSessions(c *gin.Context) {
+	if err := h.se.revokeOtherSessions(c.Request.Context(), c.GetString("uid"), c.GetString("sid")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, pkghttp.Empty{})
+}
+
+// @Summary		两步验证状态
+// @Description	查询当前用户是否已启用两步验证、所属角色是否强制两步验证，以及剩余恢复码数量
+// @Security		BearerAuth
+// @ID				mfaStatus
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Success		200	{object}	pkghttp.HttpResponse[mfaStatusRes]	"查询成功"
+// @Router			/admin/auth/mfa [get]
+func (h *handler) mfaStatus(c *gin.Context) {
+	res, err := h.se.mfaStatus(c.Request.Context(), c.GetString("uid"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, res)
+}
+
+// @Summary		生成两步验证密钥
+// @Description	生成待启用的 TOTP 密钥与 otpauth URI，调用 /admin/auth/mfa/confirm 校验验证码后生效；重复调用会替换未启用的密钥
+// @Security		BearerAuth
+// @ID				mfaSetup
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Success		200	{object}	pkghttp.HttpResponse[mfaSetupRes]	"生成成功"
+// @Router			/admin/auth/mfa/setup [post]
+func (h *handler) mfaSetup(c *gin.Context) {
+	res, err := h.se.mfaSetup(c.Request.Context(), c.GetString("uid"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, res)
+}
+
+// @Summary		启用两步验证
+// @Description	提交身份验证器 App 生成的 6 位验证码，校验通过后启用两步验证并返回恢复码（仅展示一次）
+// @Security		BearerAuth
+// @ID				mfaConfirm
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			data	body		mfaCodeReq								true	"验证码"
+// @Success		200		{object}	pkghttp.HttpResponse[recoveryCodesRes]	"启用成功"
+// @Router			/admin/auth/mfa/confirm [post]
+func (h *handler) mfaConfirm(c *gin.Context) {
+	var req mfaCodeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkghttp.BindError(err))
+		return
+	}
+
+	res, err := h.se.mfaConfirm(c.Request.Context(), c.GetString("uid"), &req, clientOf(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, res)
+}
+
+// @Summary		关闭两步验证
+// @Description	提交 TOTP 验证码或恢复码关闭两步验证；所属角色强制两步验证时返回 403
+// @Security		BearerAuth
+// @ID				mfaDisable
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			data	body		mfaCodeReq					true	"验证码"
+// @Success		200		{object}	pkghttp.HttpResponse[Empty]	"关闭成功"
+// @Router			/admin/auth/mfa/disable [post]
+func (h *handler) mfaDisable(c *gin.Context) {
+	var req mfaCodeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkghttp.BindError(err))
+		return
+	}
+
+	if err := h.se.mfaDisable(c.Request.Context(), c.GetString("uid"), &req, clientOf(c)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, pkghttp.Empty{})
+}
+
+// @Summary		重新生成恢复码
+// @Description	提交 TOTP 验证码或恢复码，校验通过后旧恢复码全部作废并返回新的恢复码（仅展示一次）
+// @Security		BearerAuth
+// @ID				mfaRecoveryCodes
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			data	body		mfaCodeReq								true	"验证码"
+// @Success		200		{object}	pkghttp.HttpResponse[recoveryCodesRes]	"生成成功"
+// @Router			/admin/auth/mfa/recovery-codes [post]
+func (h *handler) mfaRecoveryCodes(c *gin.Context) {
+	var req mfaCodeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkghttp.BindError(err))
+		return
+	}
+
+	res, err := h.se.mfaRecoveryCodes(c.Request.Context(), c.GetString("uid"), &req, clientOf(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, res)
+}
+
+// clientOf 提取请求的客户端信息
+func clientOf(c *gin.Context) *client {
+	ua := c.Request.UserAgent()
+	return &client{
+		Device:    deviceOf(ua),
+		IP:        c.ClientIP(),
+		UserAgent: ua,
+	}
+}
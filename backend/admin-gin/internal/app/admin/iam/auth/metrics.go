@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"errors"
+	"mall-api/internal/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// 登录步骤：密码校验 / 两步验证
+const (
+	loginStepPassword = "password"
+	loginStepMFA      = "mfa"
+)
+
+var loginTotal = promauto.With(metrics.Registry).NewCounterVec(prometheus.CounterOpts{
+	Name: "auth_login_total",
+	Help: "登录次数，result：success 成功 / mfa_required 密码正确待两步验证 / failed 凭证错误 / locked 已锁定 / unavailable 账号不可用 / error 内部错误",
+}, []string{"step", "result"})
+
+// observeLogin 按登录结果计数
+func observeLogin(step string, mfaRequired bool, err error) {
+	result := "success"
+	switch {
+	case err == nil && mfaRequired:
+		result = "mfa_required"
+	case err == nil:
+	case errors.Is(err, errLoginFailed), errors.Is(err, errMFACodeInvalid), errors.Is(err, errMFAChallengeInvalid):
+		result = "failed"
+	case errors.Is(err, errLoginLocked):
+		result = "locked"
+	case errors.Is(err, errAccountUnavailable):
+		result = "unavailable"
+	default:
+		result = "error"
+	}
+	loginTotal.WithLabelValues(step, result).Inc()
+}
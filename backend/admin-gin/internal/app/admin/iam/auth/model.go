@@ -4,29 +4,95 @@ import "time"
 
 // account 是 auth 领域关心的最小账号信息（用于登录/注册）
 type account struct {
-	UID      string
-	Username string
-	Password string // bcrypt hash
-	Role     string
-	IsActive bool
+	UID       string
+	Username  string
+	Password  string // bcrypt hash
+	Email     string
+	IsActive  bool
+	IsDeleted bool
+
+	MustChangePassword bool // 下次登录须修改密码
+}
+
+// available 账号是否可用：已启用且未被删除
+func (a *account) available() bool {
+	return a.IsActive && !a.IsDeleted
+}
+
+// accountState 账号状态（JWT 中间件每个请求校验，缓存于 Redis）
+type accountState struct {
+	Available          bool // 已启用且未被删除
+	MustChangePassword bool // 须修改密码后才能访问其他接口
 }
 
-// auth模块 user 仅用于 GORM 映射同一张用户表（与 user 模块解耦），仅模块内部使用
-type user struct {
-	ID uint64 `gorm:"primaryKey;autoIncrement"`
+// session 服务端会话记录（存储于 Redis），一次登录对应一个会话
+// access/refresh token 通过 claims.sid 关联到会话，会话被删除即视为下线
+type session struct {
+	SID       string `json:"sid"`
+	UID       string `json:"uid"`
+	Device    string `json:"device"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+	TokenHash string `json:"token_hash"` // 当前有效 refresh token 的 SHA-256，不保存明文
 
-	UID      string `gorm:"size:32;uniqueIndex"`
-	Username string `gorm:"size:64;uniqueIndex;not null"`
-	Password string `gorm:"size:255;not null"`
+	PrevTokenHash string    `json:"prev_token_hash"` // 上一个（已轮换）refresh token 的摘要，用于区分并发刷新与令牌重放
+	RotatedAt     time.Time `json:"rotated_at"`      // 最近一次轮换时间
 
-	Role     string `gorm:"size:32;default:'admin'"`
-	IsActive bool   `gorm:"default:true"`
+	AccessJTI       string    `json:"access_jti"`        // 最近签发的 access token 的 jti，会话下线时写入吊销名单
+	AccessExpiresAt time.Time `json:"access_expires_at"` // 最近签发的 access token 的过期时间
 
-	IsDeleted bool `gorm:"default:false"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"` // 登录、刷新令牌时更新
+}
+
+// loginGuard 登录防暴力破解状态（按用户名与 IP 两个维度统计）
+type loginGuard struct {
+	UserFailures int64 // 用户名在窗口期内的失败次数
+	IPFailures   int64 // IP 在窗口期内的失败次数
+	UserLocked   bool  // 用户名是否被锁定
+	IPLocked     bool  // IP 是否被锁定
+}
+
+// client 发起请求的客户端信息
+type client struct {
+	Device    string
+	IP        string
+	UserAgent string
+}
 
+// MFA 用户两步验证（TOTP）绑定信息
+type MFA struct {
+	/** 用户 UID */
+	UserUID string `gorm:"size:32;primaryKey"`
+
+	/** TOTP 密钥（Base32），生成后需校验一次验证码才会启用 */
+	Secret string `gorm:"size:64;not null"`
+
+	/** 是否已启用 */
+	Enabled bool `gorm:"default:false"`
+
+	/** 恢复码的 SHA-256 摘要，使用一次后移除 */
+	RecoveryCodes []string `gorm:"type:text;serializer:json"`
+
+	/** 最近一次通过校验的 TOTP 时间步，同一时间步内的验证码不可重复使用 */
+	LastUsedStep int64 `gorm:"default:0"`
+
+	/** 启用时间 */
+	EnabledAt *time.Time
+
+	/** 创建时间 */
 	CreatedAt time.Time
+
+	/** 更新时间 */
 	UpdatedAt time.Time
 }
 
-// TableName 强制 auth.user 使用 user 表
-func (user) TableName() string { return "user" }
+// TableName 两步验证信息使用 user_mfa 表
+func (MFA) TableName() string { return "user_mfa" }
+
+// mfaChallenge 登录两步验证挑战（存储于 Redis）：密码校验通过后签发，凭挑战令牌完成第二步
+type mfaChallenge struct {
+	UID      string // 用户 UID
+	Username string // 规范化后的用户名，验证码错误时计入登录失败次数
+	Setup    bool   // 是否为强制绑定：所属角色要求两步验证但用户尚未绑定
+}
@@ -3,16 +3,23 @@ package auth
 import (
 	"mall-api/internal/pkg/cookie"
 	"mall-api/internal/pkg/jwt"
+	"mall-api/internal/pkg/logger"
+	"mall-api/internal/pkg/mail"
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
-func Register(rg *gin.RouterGroup, db *gorm.DB, rdb *redis.Client, jt *jwt.JWT, ck *cookie.CookieManager) {
-	repo := newRepository(db, rdb)
-	svc := newService(repo, jt)
+// log 模块日志，级别由配置 log.levels.auth 控制（未配置时跟随全局级别）
+var log = logger.Component("auth")
+
+func Register(rg *gin.RouterGroup, db *gorm.DB, rdb *redis.Client, jt *jwt.JWT, ck *cookie.CookieManager, ml mail.Mailer, accounts accountStore, roles userRoles, cfg Config) Authenticator {
+	repo := newRepository(db, rdb, accounts)
+	svc := newService(repo, jt, ml, roles, cfg)
 	h := newHandler(svc, ck)
 
 	registerRouter(rg, h)
+
+	return svc
 }
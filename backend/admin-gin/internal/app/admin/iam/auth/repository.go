@@ -2,101 +2,728 @@ package auth
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
 
+	"mall-api/internal/app/admin/identity"
+
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type repository interface {
-	findUserIsExist(username string) (bool, error)    // 查找用户是否存在
-	createUser(account *account) error                // 创建新用户
-	findUserByName(username string) (*account, error) // 根据用户名查找用户
+	findUserIsExist(ctx context.Context, username string) (bool, error)    // 查找用户是否存在
+	createUser(ctx context.Context, account *account) error                // 创建新用户
+	findUserByName(ctx context.Context, username string) (*account, error) // 根据用户名查找用户
+	findUserState(ctx context.Context, uid string) (*accountState, error)  // 查询账号状态（用户不存在视为不可用）
+
+	setSession(ctx context.Context, s *session, ttl, indexTTL time.Duration) error                       // 写入会话
+	getSession(ctx context.Context, uid, sid string) (*session, error)                                   // 获取会话
+	rotateSession(ctx context.Context, s *session, expectHash string, ttl, indexTTL time.Duration) error // 轮换会话的 refresh token（CAS）
+	listSessions(ctx context.Context, uid string) ([]session, error)                                     // 列出用户全部会话
+	delSessions(ctx context.Context, uid string, sids ...string) error                                   // 删除会话
+
+	denyTokens(ctx context.Context, jtis map[string]time.Duration) error                   // 将 access token 加入吊销名单（jti -> 剩余寿命）
+	denyUserBefore(ctx context.Context, uid string, at time.Time, ttl time.Duration) error // 吊销用户在 at 之前签发的全部 token（毫秒精度）
+	isDenied(ctx context.Context, uid, jti string, issuedAt time.Time) (bool, error)       // 判断 token 是否已被吊销
+
+	getLoginGuard(ctx context.Context, username, ip string) (*loginGuard, error)                          // 查询登录失败计数与锁定状态
+	incrLoginFailure(ctx context.Context, username, ip string, window time.Duration) (*loginGuard, error) // 累加登录失败次数
+	lockLogin(ctx context.Context, username, ip string, ttl time.Duration) error                          // 锁定用户名 / IP（传空表示不锁定该维度）
+	clearLoginFailure(ctx context.Context, username, ip string) error                                     // 清除失败计数与锁定（传空表示不处理该维度）
+
+	getStatusCache(ctx context.Context, uid string) (*accountState, bool, error)                  // 读取账号状态缓存，bool 表示是否命中
+	setStatusCache(ctx context.Context, uid string, state *accountState, ttl time.Duration) error // 写入账号状态缓存
+	delStatusCache(ctx context.Context, uid string) error                                         // 删除账号状态缓存（状态变更时失效）
+
+	findUserByUID(ctx context.Context, uid string) (*account, error)                              // 根据 UID 查找用户
+	findUserByEmail(ctx context.Context, email string) (*account, error)                          // 根据邮箱查找未删除的用户（忽略大小写）
+	passwordHistory(ctx context.Context, uid string, limit int) ([]string, error)                 // 查询最近 limit 个历史密码 hash
+	changePassword(ctx context.Context, uid, hash, oldHash string, keep int) error                // 修改密码并清除强制修改标记，旧密码写入历史（保留最近 keep 个）
+	getMFA(ctx context.Context, uid string) (*MFA, error)                                         // 查询两步验证信息
+	saveMFASecret(ctx context.Context, uid, secret string) error                                  // 写入待启用的 TOTP 密钥（已启用时返回 errMFAAlreadyEnabled）
+	enableMFA(ctx context.Context, uid string, recoveryCodes []string, step int64) error          // 启用两步验证并写入恢复码摘要
+	deleteMFA(ctx context.Context, uid string) error                                              // 删除两步验证信息（关闭）
+	useMFAStep(ctx context.Context, uid string, step int64) (bool, error)                         // 记录已使用的 TOTP 时间步，时间步已被使用时返回 false
+	replaceRecoveryCodes(ctx context.Context, uid string, recoveryCodes []string) error           // 全量替换恢复码摘要
+	consumeRecoveryCode(ctx context.Context, uid, hash string) (bool, error)                      // 使用恢复码，不存在时返回 false
+	setMFAChallenge(ctx context.Context, token string, ch *mfaChallenge, ttl time.Duration) error // 写入两步验证挑战
+	getMFAChallenge(ctx context.Context, token string) (*mfaChallenge, error)                     // 获取两步验证挑战
+	failMFAChallenge(ctx context.Context, token string, maxAttempts int64) error                  // 累加挑战的错误次数，达到上限时作废挑战
+	delMFAChallenge(ctx context.Context, token string) (bool, error)                              // 作废挑战，返回是否由本次调用删除（防止并发重复使用）
+
+	allowPasswordResetMail(ctx context.Context, uid string, interval time.Duration) (bool, error) // 重置邮件发送频率限制，interval 内已发送过时返回 false
+	setPasswordReset(ctx context.Context, uid, tokenHash string, ttl time.Duration) error         // 写入重置令牌，同时作废该用户此前未使用的令牌
+	getPasswordReset(ctx context.Context, tokenHash string) (string, error)                       // 查询重置令牌对应的用户 UID（无效时返回 errResetTokenInvalid）
+	consumePasswordReset(ctx context.Context, uid, tokenHash string) (bool, error)                // 使用重置令牌，返回是否由本次调用作废（防止并发重复使用）
+	delPasswordReset(ctx context.Context, uid string) error                                       // 作废用户未使用的重置令牌
 
-	setRefreshToken(ctx context.Context, uid string, token string, duration time.Duration) error // 设置刷新令牌
-	getRefreshToken(ctx context.Context, uid string) (string, error)                             // 设置刷新令牌
-	delRefreshToken(ctx context.Context, uid string) error                                       // 删除刷新令牌
+	deleteUserData(ctx context.Context, uids []string) error // 删除用户的两步验证信息（用户被彻底删除时，历史密码由 identity 模块一并删除）
+}
+
+// accountStore 用户表数据访问（由 identity.Store 实现，经 Register 注入），auth 不再单独映射 user 表
+type accountStore interface {
+	ExistsByUsername(ctx context.Context, username string) (bool, error)
+	Create(ctx context.Context, u *identity.User) error
+	GetByUsername(ctx context.Context, username string) (*identity.User, error)
+	GetByUID(ctx context.Context, uid string) (*identity.User, error)
+	GetByEmail(ctx context.Context, email string) (*identity.User, error)
+	PasswordHistory(ctx context.Context, uid string, limit int) ([]string, error)
+	ChangePassword(ctx context.Context, uid, hash, oldHash string, keep int) error
 }
 
 type repo struct {
-	db  *gorm.DB
-	rdb *redis.Client
+	db       *gorm.DB
+	rdb      *redis.Client
+	accounts accountStore
 }
 
-func newRepository(db *gorm.DB, rdb *redis.Client) repository {
-	return &repo{db: db, rdb: rdb}
+func newRepository(db *gorm.DB, rdb *redis.Client, accounts accountStore) repository {
+	return &repo{db: db, rdb: rdb, accounts: accounts}
 }
 
 // 查找数据库是否存在该用户，true: 用户存在；false: 用户不存在
-func (r *repo) findUserIsExist(username string) (bool, error) {
-	var count int64
-	if err := r.db.Model(&user{}).Where("username = ?", username).Count(&count).Error; err != nil {
-		return false, err
-	}
-	return count > 0, nil
+func (r *repo) findUserIsExist(ctx context.Context, username string) (bool, error) {
+	return r.accounts.ExistsByUsername(ctx, username)
 }
 
 // 新增用户记录
-func (r *repo) createUser(account *account) error {
-	m := &user{
-		UID:       account.UID,
-		Username:  account.Username,
-		Password:  account.Password,
-		Role:      account.Role,
-		IsActive:  account.IsActive,
-		IsDeleted: false,
+func (r *repo) createUser(ctx context.Context, account *account) error {
+	return r.accounts.Create(ctx, &identity.User{
+		UID:      account.UID,
+		Username: account.Username,
+		Password: account.Password,
+		IsActive: account.IsActive,
+	})
+}
+
+// toAccount 转换为 auth 领域的账号信息
+func toAccount(u *identity.User) *account {
+	return &account{
+		UID:       u.UID,
+		Username:  u.Username,
+		Password:  u.Password,
+		Email:     u.Email,
+		IsActive:  u.IsActive,
+		IsDeleted: u.DeletedAt.Valid,
+
+		MustChangePassword: u.MustChangePassword,
 	}
-	return r.db.Create(m).Error
 }
 
 // 查找用户记录
-func (r *repo) findUserByName(username string) (*account, error) {
-	var m user
-	if err := r.db.Where("username = ?", username).First(&m).Error; err != nil {
+func (r *repo) findUserByName(ctx context.Context, username string) (*account, error) {
+	u, err := r.accounts.GetByUsername(ctx, username)
+	if err != nil {
 		return nil, err
 	}
+	return toAccount(u), nil
+}
 
-	return &account{
-		UID:      m.UID,
-		Username: m.Username,
-		Password: m.Password,
-		Role:     m.Role,
-		IsActive: m.IsActive,
+// 查询账号状态，用户不存在（含已删除）视为不可用
+func (r *repo) findUserState(ctx context.Context, uid string) (*accountState, error) {
+	u, err := r.accounts.GetByUID(ctx, uid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &accountState{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &accountState{
+		Available:          u.IsActive,
+		MustChangePassword: u.MustChangePassword,
 	}, nil
 }
 
-// 设置 refresh token
-func (r *repo) setRefreshToken(ctx context.Context, uid string, token string, duration time.Duration) error {
-	// Key 格式建议: "模块:功能:ID" -> "auth:refresh:10086"
-	key := fmt.Sprintf("auth:refresh:%s", uid)
+// 会话 Key："auth:session:<uid>:<sid>" 保存会话详情；"auth:sessions:<uid>" 为有序集合，score 为最近活跃时间
+func sessionKey(uid, sid string) string {
+	return fmt.Sprintf("auth:session:%s:%s", uid, sid)
+}
 
-	// Redis 命令: SET key value EX duration
-	// duration (比如 7天) 会自动转换为 Redis 的秒数
-	return r.rdb.Set(ctx, key, token, duration).Err()
+func sessionIndexKey(uid string) string {
+	return fmt.Sprintf("auth:sessions:%s", uid)
 }
 
-// 获取 refresh token
-func (r *repo) getRefreshToken(ctx context.Context, uid string) (string, error) {
-	key := fmt.Sprintf("auth:refresh:%s", uid)
+// 写入会话（新增或覆盖），ttl 与 refresh token 剩余寿命保持一致
+// indexTTL 为会话索引的过期时间，应不短于任一会话的剩余寿命（通常取 refresh token 完整有效期）
+func (r *repo) setSession(ctx context.Context, s *session, ttl, indexTTL time.Duration) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	indexKey := sessionIndexKey(s.UID)
+	_, err = r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, sessionKey(s.UID, s.SID), data, ttl)
+		pipe.ZAdd(ctx, indexKey, redis.Z{Score: float64(s.LastSeenAt.Unix()), Member: s.SID})
+		pipe.Expire(ctx, indexKey, indexTTL)
+		return nil
+	})
+	return err
+}
 
-	// Redis 命令: GET key
-	val, err := r.rdb.Get(ctx, key).Result()
+// 获取会话
+func (r *repo) getSession(ctx context.Context, uid, sid string) (*session, error) {
+	data, err := r.rdb.Get(ctx, sessionKey(uid, sid)).Bytes()
 	if err == redis.Nil {
-		// 返回自定义的通用错误，屏蔽底层细节
-		return "", errors.New("Refresh Token Not Found")
+		return nil, errSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var s session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// 轮换会话的 refresh token：仅当 Redis 中的摘要仍为 expectHash 时写入 s
+// 基于 WATCH 实现乐观锁，并发刷新时只有一个请求能成功，其余返回 errRefreshTokenInvalid
+func (r *repo) rotateSession(ctx context.Context, s *session, expectHash string, ttl, indexTTL time.Duration) error {
+	key := sessionKey(s.UID, s.SID)
+	err := r.rdb.Watch(ctx, func(tx *redis.Tx) error {
+		data, err := tx.Get(ctx, key).Bytes()
+		if err == redis.Nil {
+			return errSessionNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		var cur session
+		if err := json.Unmarshal(data, &cur); err != nil {
+			return err
+		}
+		if cur.TokenHash != expectHash {
+			return errRefreshTokenInvalid
+		}
+
+		next, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		indexKey := sessionIndexKey(s.UID)
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, next, ttl)
+			pipe.ZAdd(ctx, indexKey, redis.Z{Score: float64(s.LastSeenAt.Unix()), Member: s.SID})
+			pipe.Expire(ctx, indexKey, indexTTL)
+			return nil
+		})
+		return err
+	}, key)
+
+	if err == redis.TxFailedErr {
+		return errRefreshTokenInvalid
+	}
+	return err
+}
+
+// 列出用户全部会话（按最近活跃时间倒序），顺带清理索引中已过期的会话
+func (r *repo) listSessions(ctx context.Context, uid string) ([]session, error) {
+	indexKey := sessionIndexKey(uid)
+	sids, err := r.rdb.ZRevRange(ctx, indexKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(sids) == 0 {
+		return []session{}, nil
+	}
+
+	keys := make([]string, 0, len(sids))
+	for _, sid := range sids {
+		keys = append(keys, sessionKey(uid, sid))
+	}
+	vals, err := r.rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]session, 0, len(vals))
+	var expired []any
+	for i, v := range vals {
+		str, ok := v.(string)
+		if !ok {
+			expired = append(expired, sids[i])
+			continue
+		}
+		var s session
+		if err := json.Unmarshal([]byte(str), &s); err != nil {
+			return nil, err
+		}
+		res = append(res, s)
+	}
+
+	if len(expired) > 0 {
+		if err := r.rdb.ZRem(ctx, indexKey, expired...).Err(); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// 删除会话
+func (r *repo) delSessions(ctx context.Context, uid string, sids ...string) error {
+	if len(sids) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(sids))
+	members := make([]any, 0, len(sids))
+	for _, sid := range sids {
+		keys = append(keys, sessionKey(uid, sid))
+		members = append(members, sid)
+	}
+
+	_, err := r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, keys...)
+		pipe.ZRem(ctx, sessionIndexKey(uid), members...)
+		return nil
+	})
+	return err
+}
+
+// 吊销名单 Key："auth:deny:jti:<jti>" 按 token 吊销；"auth:deny:uid:<uid>" 保存用户级吊销时间点（秒级时间戳）
+func denyJTIKey(jti string) string {
+	return fmt.Sprintf("auth:deny:jti:%s", jti)
+}
+
+func denyUIDKey(uid string) string {
+	return fmt.Sprintf("auth:deny:uid:%s", uid)
+}
+
+// 将 access token 加入吊销名单，名单项在 token 自然过期后自动清除
+func (r *repo) denyTokens(ctx context.Context, jtis map[string]time.Duration) error {
+	if len(jtis) == 0 {
+		return nil
+	}
+
+	_, err := r.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for jti, ttl := range jtis {
+			if jti == "" || ttl <= 0 {
+				continue
+			}
+			pipe.Set(ctx, denyJTIKey(jti), 1, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+// 记录用户级吊销时间点（毫秒），ttl 取 access token 完整有效期即可（更早签发的 token 已自然过期）
+func (r *repo) denyUserBefore(ctx context.Context, uid string, at time.Time, ttl time.Duration) error {
+	return r.rdb.Set(ctx, denyUIDKey(uid), at.UnixMilli(), ttl).Err()
+}
+
+// legacyDenyBeforeMillis 小于该值的吊销时间点为升级前以秒记录的取值（毫秒时间戳自 1973 年起即大于该值）
+const legacyDenyBeforeMillis = 100_000_000_000
+
+// 判断 token 是否已被吊销：jti 在名单中，或签发时间早于用户级吊销时间点（毫秒精度，吊销后立即签发的 token 不受影响）
+func (r *repo) isDenied(ctx context.Context, uid, jti string, issuedAt time.Time) (bool, error) {
+	pipe := r.rdb.Pipeline()
+	jtiCmd := pipe.Exists(ctx, denyJTIKey(jti))
+	uidCmd := pipe.Get(ctx, denyUIDKey(uid))
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return false, err
+	}
+
+	if jtiCmd.Val() > 0 {
+		return true, nil
+	}
+
+	before, err := uidCmd.Int64()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if before < legacyDenyBeforeMillis {
+		before = (before + 1) * 1000 // 升级前以秒记录的吊销时间点：该秒及之前签发的 token 均失效
+	}
+	return issuedAt.UnixMilli() < before, nil
+}
+
+// 登录防护 Key："auth:login:fail:<user|ip>:<id>" 失败计数；"auth:login:lock:<user|ip>:<id>" 锁定标记
+func loginFailKey(kind, id string) string {
+	return fmt.Sprintf("auth:login:fail:%s:%s", kind, id)
+}
+
+func loginLockKey(kind, id string) string {
+	return fmt.Sprintf("auth:login:lock:%s:%s", kind, id)
+}
+
+// 查询登录失败计数与锁定状态
+func (r *repo) getLoginGuard(ctx context.Context, username, ip string) (*loginGuard, error) {
+	pipe := r.rdb.Pipeline()
+	userFail := pipe.Get(ctx, loginFailKey("user", username))
+	ipFail := pipe.Get(ctx, loginFailKey("ip", ip))
+	locked := pipe.Exists(ctx, loginLockKey("user", username))
+	ipLocked := pipe.Exists(ctx, loginLockKey("ip", ip))
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	g := &loginGuard{
+		UserLocked: locked.Val() > 0,
+		IPLocked:   ipLocked.Val() > 0,
+	}
+	// 计数不存在时 Int64 返回 redis.Nil，按 0 处理
+	g.UserFailures, _ = userFail.Int64()
+	g.IPFailures, _ = ipFail.Int64()
+	return g, nil
+}
+
+// 累加登录失败次数（滑动窗口：最近一次失败后 window 内无新的失败则计数自动清零）
+func (r *repo) incrLoginFailure(ctx context.Context, username, ip string, window time.Duration) (*loginGuard, error) {
+	userKey, ipKey := loginFailKey("user", username), loginFailKey("ip", ip)
+
+	pipe := r.rdb.TxPipeline()
+	userFail := pipe.Incr(ctx, userKey)
+	pipe.Expire(ctx, userKey, window)
+	ipFail := pipe.Incr(ctx, ipKey)
+	pipe.Expire(ctx, ipKey, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
 	}
 
-	// 处理其他错误（如 Redis 连接断开）
+	return &loginGuard{
+		UserFailures: userFail.Val(),
+		IPFailures:   ipFail.Val(),
+	}, nil
+}
+
+// 锁定用户名 / IP，锁定后清除对应的失败计数，解锁后重新计数
+func (r *repo) lockLogin(ctx context.Context, username, ip string, ttl time.Duration) error {
+	_, err := r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if username != "" {
+			pipe.Set(ctx, loginLockKey("user", username), 1, ttl)
+			pipe.Del(ctx, loginFailKey("user", username))
+		}
+		if ip != "" {
+			pipe.Set(ctx, loginLockKey("ip", ip), 1, ttl)
+			pipe.Del(ctx, loginFailKey("ip", ip))
+		}
+		return nil
+	})
+	return err
+}
+
+// 清除失败计数与锁定
+func (r *repo) clearLoginFailure(ctx context.Context, username, ip string) error {
+	var keys []string
+	if username != "" {
+		keys = append(keys, loginFailKey("user", username), loginLockKey("user", username))
+	}
+	if ip != "" {
+		keys = append(keys, loginFailKey("ip", ip), loginLockKey("ip", ip))
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return r.rdb.Del(ctx, keys...).Err()
+}
+
+// 账号状态缓存 Key："auth:status:<uid>"，值 "1" 表示可用、"2" 表示可用但须修改密码、"0" 表示已禁用/已删除/不存在
+func statusCacheKey(uid string) string {
+	return fmt.Sprintf("auth:status:%s", uid)
+}
+
+func (r *repo) getStatusCache(ctx context.Context, uid string) (*accountState, bool, error) {
+	val, err := r.rdb.Get(ctx, statusCacheKey(uid)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil // 缓存未命中
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return &accountState{
+		Available:          val == "1" || val == "2",
+		MustChangePassword: val == "2",
+	}, true, nil
+}
+
+func (r *repo) setStatusCache(ctx context.Context, uid string, state *accountState, ttl time.Duration) error {
+	val := "0"
+	switch {
+	case state.Available && state.MustChangePassword:
+		val = "2"
+	case state.Available:
+		val = "1"
+	}
+	return r.rdb.Set(ctx, statusCacheKey(uid), val, ttl).Err()
+}
+
+func (r *repo) delStatusCache(ctx context.Context, uid string) error {
+	return r.rdb.Del(ctx, statusCacheKey(uid)).Err()
+}
+
+// 根据 UID 查找用户
+func (r *repo) findUserByUID(ctx context.Context, uid string) (*account, error) {
+	u, err := r.accounts.GetByUID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return toAccount(u), nil
+}
+
+// 根据邮箱查找未删除的用户，邮箱忽略大小写
+func (r *repo) findUserByEmail(ctx context.Context, email string) (*account, error) {
+	u, err := r.accounts.GetByEmail(ctx, email)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	return toAccount(u), nil
+}
+
+// 查询两步验证信息，未绑定时返回 gorm.ErrRecordNotFound
+func (r *repo) getMFA(ctx context.Context, uid string) (*MFA, error) {
+	var m MFA
+	if err := r.db.WithContext(ctx).Where("user_uid = ?", uid).First(&m).Error; err != nil {
+		return nil, err
 	}
-	return val, nil
+	return &m, nil
 }
 
-// 删除 refresh token
-func (r *repo) delRefreshToken(ctx context.Context, uid string) error {
-	key := fmt.Sprintf("auth:refresh:%s", uid)
-	return r.rdb.Del(ctx, key).Err()
+// 写入待启用的 TOTP 密钥：未绑定时新增，未启用时覆盖旧密钥，已启用时不做修改
+func (r *repo) saveMFASecret(ctx context.Context, uid, secret string) error {
+	now := time.Now()
+	res := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_uid"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "recovery_codes", "last_used_step", "updated_at"}),
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "user_mfa.enabled", Value: false}}},
+	}).Create(&MFA{
+		UserUID:       uid,
+		Secret:        secret,
+		RecoveryCodes: []string{},
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errMFAAlreadyEnabled
+	}
+	return nil
+}
+
+// 启用两步验证：仅当处于待启用状态时写入，step 为确认时使用的验证码时间步
+func (r *repo) enableMFA(ctx context.Context, uid string, recoveryCodes []string, step int64) error {
+	now := time.Now()
+	res := r.db.WithContext(ctx).
+		Model(&MFA{}).
+		Where("user_uid = ? AND enabled = ?", uid, false).
+		Select("enabled", "recovery_codes", "last_used_step", "enabled_at", "updated_at").
+		Updates(&MFA{
+			Enabled:       true,
+			RecoveryCodes: recoveryCodes,
+			LastUsedStep:  step,
+			EnabledAt:     &now,
+			UpdatedAt:     now,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errMFAAlreadyEnabled
+	}
+	return nil
+}
+
+func (r *repo) deleteMFA(ctx context.Context, uid string) error {
+	return r.db.WithContext(ctx).Where("user_uid = ?", uid).Delete(&MFA{}).Error
+}
+
+// 记录已使用的 TOTP 时间步：条件更新保证同一时间步（及更早）的验证码只能成功使用一次
+func (r *repo) useMFAStep(ctx context.Context, uid string, step int64) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&MFA{}).
+		Where("user_uid = ? AND enabled = ? AND last_used_step < ?", uid, true, step).
+		Updates(map[string]any{"last_used_step": step, "updated_at": time.Now()})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *repo) replaceRecoveryCodes(ctx context.Context, uid string, recoveryCodes []string) error {
+	return r.db.WithContext(ctx).
+		Model(&MFA{}).
+		Where("user_uid = ? AND enabled = ?", uid, true).
+		Select("recovery_codes", "updated_at").
+		Updates(&MFA{RecoveryCodes: recoveryCodes, UpdatedAt: time.Now()}).Error
+}
+
+// 使用恢复码：行锁内移除匹配的摘要，并发使用同一恢复码时只有一个请求成功
+func (r *repo) consumeRecoveryCode(ctx context.Context, uid, hash string) (bool, error) {
+	var ok bool
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var m MFA
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_uid = ? AND enabled = ?", uid, true).
+			First(&m).Error; err != nil {
+			return err
+		}
+
+		rest := make([]string, 0, len(m.RecoveryCodes))
+		for _, v := range m.RecoveryCodes {
+			if v == hash && !ok {
+				ok = true
+				continue
+			}
+			rest = append(rest, v)
+		}
+		if !ok {
+			return nil
+		}
+		return tx.Model(&MFA{}).
+			Where("user_uid = ?", uid).
+			Select("recovery_codes", "updated_at").
+			Updates(&MFA{RecoveryCodes: rest, UpdatedAt: time.Now()}).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	return ok, err
+}
+
+// 两步验证挑战 Key："auth:mfa:challenge:<token>"，Hash 结构：uid / username / setup / attempts
+func mfaChallengeKey(token string) string {
+	return fmt.Sprintf("auth:mfa:challenge:%s", token)
+}
+
+func (r *repo) setMFAChallenge(ctx context.Context, token string, ch *mfaChallenge, ttl time.Duration) error {
+	key := mfaChallengeKey(token)
+	_, err := r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, "uid", ch.UID, "username", ch.Username, "setup", ch.Setup, "attempts", 0)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	return err
+}
+
+func (r *repo) getMFAChallenge(ctx context.Context, token string) (*mfaChallenge, error) {
+	vals, err := r.rdb.HGetAll(ctx, mfaChallengeKey(token)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if vals["uid"] == "" {
+		return nil, errMFAChallengeInvalid
+	}
+	return &mfaChallenge{
+		UID:      vals["uid"],
+		Username: vals["username"],
+		Setup:    vals["setup"] == "1",
+	}, nil
+}
+
+// failMFAChallengeScript 原子地累加错误次数：挑战已过期时不做处理（避免重建无 TTL 的 Key），达到上限时删除
+var failMFAChallengeScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+local n = redis.call("HINCRBY", KEYS[1], "attempts", 1)
+if n >= tonumber(ARGV[1]) then
+	redis.call("DEL", KEYS[1])
+end
+return n
+`)
+
+func (r *repo) failMFAChallenge(ctx context.Context, token string, maxAttempts int64) error {
+	return failMFAChallengeScript.Run(ctx, r.rdb, []string{mfaChallengeKey(token)}, maxAttempts).Err()
+}
+
+func (r *repo) delMFAChallenge(ctx context.Context, token string) (bool, error) {
+	n, err := r.rdb.Del(ctx, mfaChallengeKey(token)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// 查询最近 limit 个历史密码 hash（按记录时间倒序）
+func (r *repo) passwordHistory(ctx context.Context, uid string, limit int) ([]string, error) {
+	return r.accounts.PasswordHistory(ctx, uid, limit)
+}
+
+// 修改密码并清除强制修改标记，旧密码写入历史（保留最近 keep 个）
+func (r *repo) changePassword(ctx context.Context, uid, hash, oldHash string, keep int) error {
+	return r.accounts.ChangePassword(ctx, uid, hash, oldHash, keep)
+}
+
+// 找回密码 Key："auth:pwdreset:token:<sha256>" 保存令牌对应的 uid；"auth:pwdreset:user:<uid>" 保存该用户当前有效令牌的摘要
+// "auth:pwdreset:throttle:<uid>" 限制重置邮件的发送频率
+func passwordResetKey(tokenHash string) string {
+	return fmt.Sprintf("auth:pwdreset:token:%s", tokenHash)
+}
+
+func passwordResetUserKey(uid string) string {
+	return fmt.Sprintf("auth:pwdreset:user:%s", uid)
+}
+
+func passwordResetThrottleKey(uid string) string {
+	return fmt.Sprintf("auth:pwdreset:throttle:%s", uid)
+}
+
+func (r *repo) allowPasswordResetMail(ctx context.Context, uid string, interval time.Duration) (bool, error) {
+	return r.rdb.SetNX(ctx, passwordResetThrottleKey(uid), 1, interval).Result()
+}
+
+func (r *repo) setPasswordReset(ctx context.Context, uid, tokenHash string, ttl time.Duration) error {
+	userKey := passwordResetUserKey(uid)
+	prev, err := r.rdb.Get(ctx, userKey).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+
+	_, err = r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if prev != "" {
+			pipe.Del(ctx, passwordResetKey(prev))
+		}
+		pipe.Set(ctx, passwordResetKey(tokenHash), uid, ttl)
+		pipe.Set(ctx, userKey, tokenHash, ttl)
+		return nil
+	})
+	return err
+}
+
+func (r *repo) getPasswordReset(ctx context.Context, tokenHash string) (string, error) {
+	uid, err := r.rdb.Get(ctx, passwordResetKey(tokenHash)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", errResetTokenInvalid
+	}
+	return uid, err
+}
+
+func (r *repo) consumePasswordReset(ctx context.Context, uid, tokenHash string) (bool, error) {
+	n, err := r.rdb.Del(ctx, passwordResetKey(tokenHash)).Result()
+	if err != nil || n == 0 {
+		return false, err
+	}
+	return true, r.rdb.Del(ctx, passwordResetUserKey(uid)).Err()
+}
+
+func (r *repo) delPasswordReset(ctx context.Context, uid string) error {
+	userKey := passwordResetUserKey(uid)
+	tokenHash, err := r.rdb.Get(ctx, userKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return r.rdb.Del(ctx, passwordResetKey(tokenHash), userKey).Err()
+}
+
+// 删除用户的两步验证信息
+func (r *repo) deleteUserData(ctx context.Context, uids []string) error {
+	if len(uids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("user_uid IN ?", uids).Delete(&MFA{}).Error
 }
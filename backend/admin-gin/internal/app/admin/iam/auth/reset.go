@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"embed"
+	"io/fs"
+	"net/url"
+	"strings"
+
+	"mall-api/internal/pkg/mail"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// mailTemplates 内置邮件模板（templates/<name>.<lang>.tmpl）
+var mailTemplates = func() *mail.Templates {
+	sub, err := fs.Sub(templateFS, "templates")
+	if err != nil {
+		panic(err)
+	}
+	return mail.MustParseTemplates(sub)
+}()
+
+// 邮件支持的语言，其他语言回退到 mail.DefaultLang
+var mailLangs = []string{"zh", "en"}
+
+// passwordResetMail 重置密码邮件模板数据
+type passwordResetMail struct {
+	AppName  string
+	Username string
+	Link     string // 前端重置密码页链接（未配置 PasswordResetURL 时为空，邮件中直接展示令牌）
+	Token    string
+	TTL      int // 有效期（分钟）
+}
+
+// resetLink 在前端重置密码页地址上附加 token 参数，未配置地址时返回空
+func resetLink(base, token string) string {
+	if base == "" {
+		return ""
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return ""
+	}
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// mailLang 选择邮件语言：优先使用请求参数，其次取 Accept-Language 的首选语言，如 "en-US,en;q=0.9" -> "en"
+func mailLang(lang, acceptLanguage string) string {
+	if lang == "" {
+		lang, _, _ = strings.Cut(acceptLanguage, ",")
+	}
+	lang, _, _ = strings.Cut(strings.ToLower(strings.TrimSpace(lang)), ";")
+	lang, _, _ = strings.Cut(lang, "-")
+	for _, v := range mailLangs {
+		if lang == v {
+			return v
+		}
+	}
+	return mail.DefaultLang
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"mall-api/internal/app/admin/iam/role"
 	"mall-api/internal/pkg/middleware"
 
 	"github.com/gin-gonic/gin"
@@ -14,13 +15,34 @@ func registerRouter(r *gin.RouterGroup, handlers *handler) {
 
 		publicGroup.POST("/register", handlers.register)
 		publicGroup.POST("/login", handlers.login)
-		publicGroup.POST("/session/refresh", handlers.refresh) // session 前缀用于路径匹配 cookie 添加 refersh token
+		publicGroup.POST("/login/mfa", handlers.loginMFA)             // 两步验证：凭登录返回的 mfa_token 完成登录
+		publicGroup.POST("/login/mfa/setup", handlers.loginMFASetup)  // 两步验证：角色强制要求但尚未绑定时生成密钥
+		publicGroup.POST("/session/refresh", handlers.refresh)        // session 前缀用于路径匹配 cookie 添加 refersh token
+		publicGroup.POST("/password/forgot", handlers.forgotPassword) // 找回密码：发送重置邮件
+		publicGroup.POST("/password/reset", handlers.resetPassword)   // 找回密码：凭邮件中的令牌设置新密码
+	}
+
+	// 需要鉴权，且允许 "须修改密码" 的账号访问
+	passwordGroup := r.Group("/auth")
+	passwordGroup.Use(middleware.JWT(middleware.AllowMustChangePassword()))
+	{
+		passwordGroup.PUT("/password", handlers.changePassword)
+		passwordGroup.POST("/session/logout", handlers.logout) // session 前缀用于路径匹配 cookie 添加 refersh token
 	}
 
 	// 需要鉴权
 	authGroup := r.Group("/auth")
 	authGroup.Use(middleware.JWT())
 	{
-		authGroup.POST("/session/logout", handlers.logout) // session 前缀用于路径匹配 cookie 添加 refersh token
+		authGroup.GET("/session", handlers.listSessions)
+		authGroup.DELETE("/session/others", handlers.revokeOtherSessions)
+		authGroup.DELETE("/session/:sid", handlers.revokeSession)
+		authGroup.POST("/unlock", middleware.RequirePermission(role.PermUserUnlock), handlers.unlock)
+
+		authGroup.GET("/mfa", handlers.mfaStatus)
+		authGroup.POST("/mfa/setup", handlers.mfaSetup)
+		authGroup.POST("/mfa/confirm", handlers.mfaConfirm)
+		authGroup.POST("/mfa/disable", handlers.mfaDisable)
+		authGroup.POST("/mfa/recovery-codes", handlers.mfaRecoveryCodes)
 	}
 }
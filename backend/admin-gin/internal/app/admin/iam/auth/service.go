@@ -2,181 +2,1170 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"errors"
+	"mall-api/internal/app/admin/iam/role"
+	"mall-api/internal/app/admin/identity"
+	"mall-api/internal/pkg/errs"
 	"mall-api/internal/pkg/jwt"
+	"mall-api/internal/pkg/mail"
 	"mall-api/internal/pkg/uuid"
-	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
+var (
+	errLoginFailed         = errs.Unauthorized("用户名或密码错误") // 登录失败统一返回该错误，避免枚举用户名
+	errLoginLocked         = errs.RateLimited("登录失败次数过多，请稍后再试")
+	errAccountUnavailable  = errs.Forbidden("账号已被禁用或删除")
+	errSessionNotFound     = errs.NotFound("会话不存在或已失效")
+	errRefreshTokenInvalid = errs.Unauthorized("刷新token无效")
+	errRefreshTokenReused  = errs.Unauthorized("刷新token已被使用，会话已下线，请重新登录")
+
+	errMFAChallengeInvalid = errs.Unauthorized("两步验证已过期或无效，请重新登录")
+	errMFACodeInvalid      = errs.Unauthorized("验证码错误")
+	errMFAAlreadyEnabled   = errs.Conflict("已启用两步验证")
+	errMFANotEnabled       = errs.BadRequest("未启用两步验证")
+	errMFASetupRequired    = errs.BadRequest("请先生成两步验证密钥")
+	errMFARequired         = errs.Forbidden("所属角色要求两步验证，不可关闭")
+
+	errRegisterDisabled = errs.Forbidden("未开放注册，请联系管理员创建账号")
+	errUserExists       = errs.Conflict("用户已经存在")
+
+	errOldPasswordInvalid = errs.BadRequest("原密码错误")
+	errResetTokenInvalid  = errs.BadRequest("重置链接已过期或无效，请重新申请")
+)
+
+// statusCacheTTL 账号状态缓存有效期：状态变更时主动失效，TTL 仅作兜底
+const statusCacheTTL = time.Minute
+
+// refreshReuseGrace 并发刷新宽限期：该时间内出示刚被轮换的旧 token 不视为重放
+const refreshReuseGrace = 10 * time.Second
+
+// dummyPasswordHash 用户不存在时参与比对的 bcrypt hash，使登录耗时与用户存在时一致
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// userRoles 角色能力（由 iam/role 模块实现，经 boot 注入）
+type userRoles interface {
+	// Assign 为新注册用户分配默认角色
+	Assign(ctx context.Context, uid string, codes []string) error
+	// RequiresMFA 用户所属角色是否强制两步验证
+	RequiresMFA(ctx context.Context, uid string) (bool, error)
+}
+
+// Revoker 令牌吊销能力（经 boot 注入 JWT 中间件与 user 模块）
+type Revoker interface {
+	// IsRevoked 判断 access token 是否已被吊销（注销、会话下线、用户禁用/删除）
+	IsRevoked(ctx context.Context, claims *jwt.Claims) (bool, error)
+	// RevokeUser 吊销用户已签发的全部 token 并下线其全部会话
+	RevokeUser(ctx context.Context, uid string) error
+}
+
+// AccountStatus 账号状态校验能力（经 boot 注入 JWT 中间件与 user 模块）
+type AccountStatus interface {
+	// IsAvailable 判断账号是否可用：已启用且未被删除（优先读取 Redis 缓存）
+	IsAvailable(ctx context.Context, uid string) (bool, error)
+	// AccountState 查询账号是否可用，以及是否须先修改密码（优先读取 Redis 缓存）
+	AccountState(ctx context.Context, uid string) (available, mustChangePassword bool, err error)
+	// InvalidateStatus 账号启用状态 / 删除状态变更后失效缓存，下一次请求即生效
+	InvalidateStatus(ctx context.Context, uid string) error
+}
+
+// Authenticator auth 模块对外提供的能力
+type Authenticator interface {
+	Revoker
+	AccountStatus
+	// PurgeUsers 清理被彻底删除用户的认证数据（两步验证、历史密码、状态缓存）
+	PurgeUsers(ctx context.Context, uids []string) error
+}
+
 type service interface {
-	register(req *registerReq) error                                     // 注册
-	login(ctx context.Context, req *loginReq) (*loginRes, error)         // 登录
-	refresh(ctx context.Context, refreshToken string) (*loginRes, error) // 刷新 token
-	logout(ctx context.Context, refreshToken string) (int, error)        // 注销
+	register(ctx context.Context, req *registerReq) error                                          // 注册
+	login(ctx context.Context, req *loginReq, cl *client) (*loginRes, *mfaChallengeRes, error)     // 登录：需要两步验证时返回挑战令牌代替 token 对
+	loginMFA(ctx context.Context, req *mfaLoginReq, cl *client) (*mfaLoginRes, error)              // 登录第二步：校验验证码并签发 token 对
+	loginMFASetup(ctx context.Context, req *mfaTokenReq) (*mfaSetupRes, error)                     // 登录时强制绑定：生成 TOTP 密钥
+	refresh(ctx context.Context, refreshToken string, cl *client) (*loginRes, error)               // 刷新 token
+	logout(ctx context.Context, refreshToken string) error                                         // 注销
+	unlock(ctx context.Context, req *unlockReq) error                                              // 解除登录锁定
+	changePassword(ctx context.Context, uid, sid string, req *changePasswordReq, cl *client) error // 修改密码
+	forgotPassword(ctx context.Context, req *forgotPasswordReq, lang string) error                 // 找回密码：向邮箱发送重置链接
+	resetPassword(ctx context.Context, req *resetPasswordReq) error                                // 凭重置令牌设置新密码
+
+	listSessions(ctx context.Context, uid, sid string) ([]sessionRes, error) // 查询当前用户的在线会话
+	revokeSession(ctx context.Context, uid, sid string) error                // 下线指定会话
+	revokeOtherSessions(ctx context.Context, uid, currentSID string) error   // 下线当前会话以外的全部会话
+
+	mfaStatus(ctx context.Context, uid string) (*mfaStatusRes, error)                                         // 查询两步验证状态
+	mfaSetup(ctx context.Context, uid string) (*mfaSetupRes, error)                                           // 生成待启用的 TOTP 密钥
+	mfaConfirm(ctx context.Context, uid string, req *mfaCodeReq, cl *client) (*recoveryCodesRes, error)       // 校验验证码并启用两步验证
+	mfaDisable(ctx context.Context, uid string, req *mfaCodeReq, cl *client) error                            // 关闭两步验证
+	mfaRecoveryCodes(ctx context.Context, uid string, req *mfaCodeReq, cl *client) (*recoveryCodesRes, error) // 重新生成恢复码
 }
 
 type svc struct {
-	repo repository
-	jt   *jwt.JWT
+	repo   repository
+	jt     *jwt.JWT
+	mailer mail.Mailer
+	roles  userRoles
+	cfg    Config
 }
 
-func newService(repo repository, jt *jwt.JWT) service {
+func newService(repo repository, jt *jwt.JWT, mailer mail.Mailer, roles userRoles, cfg Config) *svc {
 	return &svc{
-		repo: repo,
-		jt:   jt,
+		repo:   repo,
+		jt:     jt,
+		mailer: mailer,
+		roles:  roles,
+		cfg:    cfg,
 	}
 }
 
 // 登陆
-func (s *svc) login(ctx context.Context, req *loginReq) (*loginRes, error) {
-	// 1. 查找用户
-	account, err := s.repo.findUserByName(req.Username)
+func (s *svc) login(ctx context.Context, req *loginReq, cl *client) (*loginRes, *mfaChallengeRes, error) {
+	username := normalizeUsername(req.Username)
+
+	// 1. 防暴力破解：用户名 / IP 被锁定时直接拒绝，否则按已失败次数渐进延迟
+	guard, err := s.repo.getLoginGuard(ctx, username, cl.IP)
+	if err != nil {
+		return nil, nil, err
+	}
+	if guard.UserLocked || guard.IPLocked {
+		return nil, nil, errLoginLocked
+	}
+	if err := s.loginDelay(ctx, max(guard.UserFailures, guard.IPFailures)); err != nil {
+		return nil, nil, err
+	}
+
+	// 2. 查找用户（不存在时不提前返回，仍执行一次密码比对，避免通过耗时差异枚举用户名）
+	account, err := s.repo.findUserByName(ctx, username)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, err
+	}
+	hash := dummyPasswordHash()
+	if account != nil {
+		hash = []byte(account.Password)
+	}
+
+	// 3. 校验用户密码是否正确（对比 hash 与明文）
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(req.Password)); err != nil || account == nil {
+		return nil, nil, s.loginFailed(ctx, username, cl.IP)
+	}
+
+	// 4. 账号状态校验：密码正确但账号已禁用 / 已删除（不计入失败次数）
+	if !account.available() {
+		return nil, nil, errAccountUnavailable
+	}
+
+	// 5. 两步验证：已启用 TOTP，或所属角色强制两步验证时，返回挑战令牌代替 token 对
+	//    此时不清除失败计数，使第二步的验证码错误同样累计到锁定阈值
+	challenge, err := s.newMFAChallenge(ctx, account.UID, username)
+	if err != nil {
+		return nil, nil, err
+	}
+	if challenge != nil {
+		return nil, challenge, nil
+	}
+
+	// 6. 登录成功：清除该用户名的失败计数（IP 计数保留，防止用一个有效账号反复重置）
+	if err := s.repo.clearLoginFailure(ctx, username, ""); err != nil {
+		return nil, nil, err
+	}
+
+	// 7. 新建会话并签发 token 对
+	tokenPair, err := s.createSession(ctx, account.UID, cl)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &loginRes{
+		UID:                account.UID,
+		AccessToken:        tokenPair.AccessToken,
+		ExpiresAt:          tokenPair.ExpiresAt,
+		RefreshToken:       tokenPair.RefreshToken,
+		MustChangePassword: account.MustChangePassword,
+	}, nil, nil
+}
+
+// 登录第二步：校验 TOTP 验证码或恢复码；强制绑定的挑战在此确认绑定并返回恢复码
+func (s *svc) loginMFA(ctx context.Context, req *mfaLoginReq, cl *client) (*mfaLoginRes, error) {
+
+	// 1. 校验挑战令牌
+	ch, err := s.repo.getMFAChallenge(ctx, req.MFAToken)
 	if err != nil {
 		return nil, err
 	}
 
-	// 2. 校验用户密码是否正确（对比 hash 与明文）
-	if err := bcrypt.CompareHashAndPassword([]byte(account.Password), []byte(req.Password)); err != nil {
-		return nil, errors.New("密码不正确")
+	// 2. 与密码登录共用锁定状态
+	guard, err := s.repo.getLoginGuard(ctx, ch.Username, cl.IP)
+	if err != nil {
+		return nil, err
+	}
+	if guard.UserLocked || guard.IPLocked {
+		return nil, errLoginLocked
 	}
 
-	// 3. 构建 token
-	tokenPair, err := s.jt.GenerateTokenPair(account.UID)
+	// 3. 校验验证码：已启用时校验 TOTP / 恢复码，强制绑定时校验 TOTP 并启用
+	m, err := s.loadMFA(ctx, ch.UID)
+	if err != nil {
+		return nil, err
+	}
+	var recoveryCodes []string
+	switch {
+	case m != nil && m.Enabled:
+		err = s.verifyMFACode(ctx, m, req.Code)
+	case ch.Setup:
+		recoveryCodes, err = s.enableMFA(ctx, m, req.Code)
+	default:
+		err = errMFAChallengeInvalid // 挑战签发后两步验证已被关闭，需重新登录
+	}
+	if errors.Is(err, errMFACodeInvalid) {
+		if err := s.repo.failMFAChallenge(ctx, req.MFAToken, mfaChallengeAttempts); err != nil {
+			return nil, err
+		}
+		return nil, s.mfaCodeFailed(ctx, ch.Username, cl.IP)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	// 4. 将 refresh token 存储到 redis
-	if err := s.repo.setRefreshToken(ctx, account.UID, tokenPair.RefreshToken, s.jt.GetRefreshExpire()); err != nil {
+	// 4. 作废挑战令牌（单次使用）
+	if ok, err := s.repo.delMFAChallenge(ctx, req.MFAToken); err != nil {
 		return nil, err
+	} else if !ok {
+		return nil, errMFAChallengeInvalid
 	}
 
-	return &loginRes{
-		UID:          account.UID,
-		AccessToken:  tokenPair.AccessToken,
-		ExpiresAt:    tokenPair.ExpiresAt,
-		RefreshToken: tokenPair.RefreshToken,
+	// 5. 账号状态复核：挑战有效期内账号可能已被禁用
+	account, err := s.repo.findUserByUID(ctx, ch.UID)
+	if err != nil {
+		return nil, err
+	}
+	if !account.available() {
+		return nil, errAccountUnavailable
+	}
+
+	// 6. 登录成功：清除该用户名的失败计数
+	if err := s.repo.clearLoginFailure(ctx, ch.Username, ""); err != nil {
+		return nil, err
+	}
+
+	// 7. 新建会话并签发 token 对
+	tokenPair, err := s.createSession(ctx, ch.UID, cl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mfaLoginRes{
+		loginRes: loginRes{
+			UID:                ch.UID,
+			AccessToken:        tokenPair.AccessToken,
+			ExpiresAt:          tokenPair.ExpiresAt,
+			RefreshToken:       tokenPair.RefreshToken,
+			MustChangePassword: account.MustChangePassword,
+		},
+		RecoveryCodes: recoveryCodes,
 	}, nil
 }
 
+// 登录时强制绑定：凭挑战令牌生成 TOTP 密钥（可重复调用，旧密钥随之作废）
+func (s *svc) loginMFASetup(ctx context.Context, req *mfaTokenReq) (*mfaSetupRes, error) {
+	ch, err := s.repo.getMFAChallenge(ctx, req.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+	if !ch.Setup {
+		return nil, errMFAAlreadyEnabled
+	}
+
+	account, err := s.repo.findUserByUID(ctx, ch.UID)
+	if err != nil {
+		return nil, err
+	}
+	return s.generateMFASecret(ctx, account)
+}
+
 // 注册
-func (s *svc) register(req *registerReq) error {
+func (s *svc) register(ctx context.Context, req *registerReq) error {
+
+	// 0. 公开注册开关（生产环境关闭，首个超级管理员由 cmd/seed 创建）
+	if !s.cfg.AllowRegister {
+		return errRegisterDisabled
+	}
+
+	// 1. 密码策略校验
+	if err := s.cfg.PasswordPolicy.Validate(req.Password, req.Username); err != nil {
+		return err
+	}
 
-	// 1. 检查用户是否已存在
-	if exist, err := s.repo.findUserIsExist(req.Username); err != nil {
+	// 2. 检查用户是否已存在
+	if exist, err := s.repo.findUserIsExist(ctx, req.Username); err != nil {
 		return err
 	} else if exist {
-		return errors.New("用户已经存在")
+		return errUserExists
 	}
 
-	// 2. 生成全局唯一 UID
+	// 3. 生成全局唯一 UID
 	uid := uuid.NewUUID()
 
-	// 3. 加密密码（bcrypt hash）
+	// 4. 加密密码（bcrypt hash）
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
 
-	// 4. 构建 auth 领域的最小账号信息（与 user 模块解耦）
+	// 5. 构建 auth 领域的最小账号信息（与 user 模块解耦）
 	account := &account{
 		UID:      uid,
 		Username: req.Username,
 		Password: string(hashedPassword),
-		Role:     "admin",
 		IsActive: true,
 	}
 
-	if err := s.repo.createUser(account); err != nil {
+	if err := s.repo.createUser(ctx, account); err != nil {
 		return err
 	}
+
+	// 6. 分配默认角色
+	return s.roles.Assign(ctx, uid, []string{role.CodeAdmin})
+}
+
+// 注销：删除 refresh token 所属的会话
+func (s *svc) logout(ctx context.Context, refreshToken string) error {
+
+	// 1. 解析 refresh token，并校验其所属会话
+	_, sess, err := s.loadSession(ctx, refreshToken)
+	if err != nil {
+		return err
+	}
+
+	// 2. 下线会话，同时吊销其 access token
+	return s.revokeSessions(ctx, sess.UID, *sess)
+}
+
+// 刷新token
+func (s *svc) refresh(ctx context.Context, refreshToken string, cl *client) (*loginRes, error) {
+
+	// 1. 解析 refresh_token，并校验其所属会话
+	claims, sess, err := s.loadSession(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 账号状态校验：已禁用 / 已删除的账号不再续期，并下线该会话
+	available, err := s.IsAvailable(ctx, sess.UID)
+	if err != nil {
+		return nil, err
+	}
+	if !available {
+		if err := s.revokeSessions(ctx, sess.UID, *sess); err != nil {
+			return nil, err
+		}
+		return nil, errs.Wrap(errs.KindUnauthorized, errAccountUnavailable) // 续期场景按 401 处理，前端据此跳转登录
+	}
+
+	// 3. 计算剩余有效期 (实现绝对过期时间，防止无限续期)
+	remaining := time.Until(claims.ExpiresAt.Time)
+	if remaining <= 0 {
+		return nil, errs.Wrap(errs.KindUnauthorized, jwt.ErrExpiredToken)
+	}
+
+	// 4. 签发新的 Token 对，沿用原会话标识
+	// Access Token 总是给满额有效期，Refresh Token 继承旧 Token 的剩余寿命
+	tokenPair, err := s.jt.RenewTokenPair(sess.UID, sess.SID, remaining)
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. 旧 access token 加入黑名单（剩余有效期内）：会话只记录最新的 jti，下线 / 吊销时无法再作废此前签发的 access token
+	if err := s.repo.denyTokens(ctx, map[string]time.Duration{sess.AccessJTI: time.Until(sess.AccessExpiresAt)}); err != nil {
+		return nil, err
+	}
+
+	// 6. 轮换会话：旧 token 摘要转入 PrevTokenHash，仅当会话仍持有旧 token 时写入成功
+	now := time.Now()
+	oldHash := sess.TokenHash
+	sess.PrevTokenHash = oldHash
+	sess.TokenHash = hashToken(tokenPair.RefreshToken)
+	sess.RotatedAt = now
+	sess.AccessJTI = tokenPair.AccessJTI
+	sess.AccessExpiresAt = time.Unix(tokenPair.ExpiresAt, 0)
+	sess.IP = cl.IP
+	sess.LastSeenAt = now
+	if err := s.repo.rotateSession(ctx, sess, oldHash, remaining, s.jt.GetRefreshExpire()); err != nil {
+		if errors.Is(err, errSessionNotFound) {
+			return nil, errs.Wrap(errs.KindUnauthorized, err) // 刷新期间会话被下线
+		}
+		return nil, err
+	}
+
+	// 7. 返回 签发的token信息，新的 refresh token 由 handler 写回 cookie
+	return &loginRes{
+		UID:          sess.UID,
+		AccessToken:  tokenPair.AccessToken,
+		ExpiresAt:    tokenPair.ExpiresAt,
+		RefreshToken: tokenPair.RefreshToken,
+	}, nil
+}
+
+// 解除登录锁定：清除用户名（以及可选的 IP）的锁定与失败计数
+func (s *svc) unlock(ctx context.Context, req *unlockReq) error {
+	return s.repo.clearLoginFailure(ctx, normalizeUsername(req.Username), strings.TrimSpace(req.IP))
+}
+
+// 修改密码：校验原密码与密码策略（含历史密码），成功后解除强制修改限制并下线其他会话
+func (s *svc) changePassword(ctx context.Context, uid, sid string, req *changePasswordReq, cl *client) error {
+
+	// 1. 锁定检查：原密码错误与登录失败共用计数，防止凭 access token 暴力猜测密码
+	account, err := s.userGuard(ctx, uid, cl.IP)
+	if err != nil {
+		return err
+	}
+
+	// 2. 校验原密码
+	if err := bcrypt.CompareHashAndPassword([]byte(account.Password), []byte(req.OldPassword)); err != nil {
+		if err := s.loginFailed(ctx, normalizeUsername(account.Username), cl.IP); !errors.Is(err, errLoginFailed) {
+			return err
+		}
+		return errOldPasswordInvalid
+	}
+
+	// 3. 密码策略校验（含历史密码）
+	hash, keep, err := s.newPasswordHash(ctx, account, req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	// 4. 保存新密码，旧密码转入历史；未使用的找回密码链接随之作废
+	if err := s.repo.changePassword(ctx, uid, hash, account.Password, keep); err != nil {
+		return err
+	}
+	if err := s.repo.delPasswordReset(ctx, uid); err != nil {
+		return err
+	}
+
+	// 5. 失效状态缓存（解除强制修改限制），并下线当前会话以外的全部会话
+	if err := s.InvalidateStatus(ctx, uid); err != nil {
+		return err
+	}
+	return s.revokeOtherSessions(ctx, uid, sid)
+}
+
+// 找回密码：邮箱对应可用账号时签发一次性重置令牌并发送邮件
+// 邮箱不存在、账号不可用或发送过于频繁时同样返回成功，避免通过该接口枚举邮箱
+func (s *svc) forgotPassword(ctx context.Context, req *forgotPasswordReq, lang string) error {
+
+	// 1. 查找账号
+	account, err := s.repo.findUserByEmail(ctx, strings.TrimSpace(req.Email))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !account.available() {
+		return nil
+	}
+
+	// 2. 发送频率限制
+	allowed, err := s.repo.allowPasswordResetMail(ctx, account.UID, passwordResetMailInterval)
+	if err != nil || !allowed {
+		return err
+	}
+
+	// 3. 签发重置令牌（Redis 中只保存摘要），同时作废此前未使用的令牌
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+	ttl := s.passwordResetTTL()
+	if err := s.repo.setPasswordReset(ctx, account.UID, hashToken(token), ttl); err != nil {
+		return err
+	}
+
+	// 4. 渲染邮件并放入发送队列（由 boot 注入的 mail.Queue 在后台发送，停机时发送完再退出）：接口耗时与邮箱是否存在无关
+	msg, err := mailTemplates.Render("password_reset", lang, &passwordResetMail{
+		AppName:  s.cfg.AppName,
+		Username: account.Username,
+		Link:     resetLink(s.cfg.PasswordResetURL, token),
+		Token:    token,
+		TTL:      int(ttl / time.Minute),
+	})
+	if err != nil {
+		return err
+	}
+	msg.To = []string{account.Email}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		log.ErrorContext(ctx, "重置邮件入队失败", "to", account.Email, "error", err.Error())
+	}
 	return nil
 }
 
-// 注销
-func (s *svc) logout(ctx context.Context, refreshToken string) (int, error) {
+// 重置密码：校验令牌与密码策略后设置新密码，令牌仅可使用一次
+// 成功后解除强制修改限制与登录锁定，并下线该用户的全部会话
+func (s *svc) resetPassword(ctx context.Context, req *resetPasswordReq) error {
+	tokenHash := hashToken(req.Token)
 
-	// 1. 解析获取 UID
-	claims, err := s.jt.ParseToken(refreshToken, "refresh")
+	// 1. 校验令牌
+	uid, err := s.repo.getPasswordReset(ctx, tokenHash)
+	if err != nil {
+		return err
+	}
+	account, err := s.repo.findUserByUID(ctx, uid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errResetTokenInvalid
+	}
+	if err != nil {
+		return err
+	}
+	if !account.available() {
+		return errAccountUnavailable
+	}
+
+	// 2. 密码策略校验（含历史密码）：校验失败时令牌不作废，可修改后重试
+	hash, keep, err := s.newPasswordHash(ctx, account, req.NewPassword)
 	if err != nil {
-		return http.StatusUnauthorized, err
+		return err
+	}
+
+	// 3. 作废令牌：并发请求中只有一个能成功
+	consumed, err := s.repo.consumePasswordReset(ctx, uid, tokenHash)
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return errResetTokenInvalid
 	}
 
-	// 2. 从jwt中提取UID
-	uid := claims.UID
+	// 4. 保存新密码，旧密码转入历史
+	if err := s.repo.changePassword(ctx, uid, hash, account.Password, keep); err != nil {
+		return err
+	}
 
-	// 3. 获取 redis 中的值
-	redisRefreshToken, err := s.repo.getRefreshToken(ctx, uid)
+	// 5. 失效状态缓存、解除登录锁定，并下线全部会话（密码可能已泄露）
+	if err := s.InvalidateStatus(ctx, uid); err != nil {
+		return err
+	}
+	if err := s.repo.clearLoginFailure(ctx, normalizeUsername(account.Username), ""); err != nil {
+		return err
+	}
+	return s.RevokeUser(ctx, uid)
+}
+
+// newPasswordHash 按密码策略校验新密码，且不可复用最近 N 次使用过的密码（当前密码 + N-1 个历史密码）
+// 返回新密码的 bcrypt hash，以及需保留的历史密码数量
+func (s *svc) newPasswordHash(ctx context.Context, account *account, newPassword string) (string, int, error) {
+	policy := s.cfg.PasswordPolicy
+	if err := policy.Validate(newPassword, account.Username); err != nil {
+		return "", 0, err
+	}
+
+	keep := max(policy.HistorySize()-1, 0)
+	if policy.HistorySize() > 0 {
+		history, err := s.repo.passwordHistory(ctx, account.UID, keep)
+		if err != nil {
+			return "", 0, err
+		}
+		if err := policy.Reused(newPassword, append([]string{account.Password}, history...)); err != nil {
+			return "", 0, err
+		}
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
 	if err != nil {
-		return http.StatusUnauthorized, err
+		return "", 0, err
+	}
+	return string(hash), keep, nil
+}
+
+// passwordResetTTL 重置令牌有效期
+func (s *svc) passwordResetTTL() time.Duration {
+	if s.cfg.PasswordResetTTL > 0 {
+		return s.cfg.PasswordResetTTL
 	}
+	return defaultPasswordResetTTL
+}
 
-	// 4. 与请求返回的 refresh token 比对
-	if redisRefreshToken != refreshToken {
-		return http.StatusUnauthorized, err
+// loginFailed 记录一次登录失败，达到阈值时锁定对应的用户名 / IP
+func (s *svc) loginFailed(ctx context.Context, username, ip string) error {
+	guard, err := s.repo.incrLoginFailure(ctx, username, ip, s.loginWindow())
+	if err != nil {
+		return err
 	}
 
-	// 5. 从 redis 中删除 refresh token
-	if err := s.repo.delRefreshToken(ctx, uid); err != nil {
-		return http.StatusInternalServerError, err
+	var lockUser, lockIP string
+	if s.cfg.LoginMaxFailures > 0 && guard.UserFailures >= int64(s.cfg.LoginMaxFailures) {
+		lockUser = username
+	}
+	if s.cfg.LoginIPMaxFailures > 0 && guard.IPFailures >= int64(s.cfg.LoginIPMaxFailures) {
+		lockIP = ip
+	}
+	if lockUser == "" && lockIP == "" {
+		return errLoginFailed
 	}
 
-	return http.StatusOK, nil
+	log.WarnContext(ctx, "登录失败次数过多，已临时锁定", "username", lockUser, "ip", lockIP)
+	if err := s.repo.lockLogin(ctx, lockUser, lockIP, s.loginLockDuration()); err != nil {
+		return err
+	}
+	return errLoginLocked
 }
 
-// 刷新token
-func (s *svc) refresh(ctx context.Context, refreshToken string) (*loginRes, error) {
+// loginDelay 渐进延迟：已失败 n 次时等待 n*step（不超过 maxLoginDelay），请求取消时提前返回
+func (s *svc) loginDelay(ctx context.Context, failures int64) error {
+	if s.cfg.LoginDelayStep <= 0 || failures <= 0 {
+		return nil
+	}
 
-	// 1. 解析 refresh_token，静态校验
+	delay := min(time.Duration(failures)*s.cfg.LoginDelayStep, maxLoginDelay)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *svc) loginWindow() time.Duration {
+	if s.cfg.LoginFailureWindow > 0 {
+		return s.cfg.LoginFailureWindow
+	}
+	return defaultLoginWindow
+}
+
+func (s *svc) loginLockDuration() time.Duration {
+	if s.cfg.LoginLockDuration > 0 {
+		return s.cfg.LoginLockDuration
+	}
+	return defaultLoginWindow
+}
+
+// 查询当前用户的全部在线会话，sid 为当前请求所属会话
+func (s *svc) listSessions(ctx context.Context, uid, sid string) ([]sessionRes, error) {
+	sessions, err := s.repo.listSessions(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]sessionRes, 0, len(sessions))
+	for _, v := range sessions {
+		res = append(res, sessionRes{
+			SID:        v.SID,
+			Device:     v.Device,
+			IP:         v.IP,
+			UserAgent:  v.UserAgent,
+			CreatedAt:  v.CreatedAt,
+			LastSeenAt: v.LastSeenAt,
+			Current:    v.SID == sid,
+		})
+	}
+	return res, nil
+}
+
+// 下线指定会话（会话按 uid 隔离，只能下线自己的会话）
+func (s *svc) revokeSession(ctx context.Context, uid, sid string) error {
+	sess, err := s.repo.getSession(ctx, uid, sid)
+	if err != nil {
+		return err
+	}
+	return s.revokeSessions(ctx, uid, *sess)
+}
+
+// 下线当前会话以外的全部会话
+func (s *svc) revokeOtherSessions(ctx context.Context, uid, currentSID string) error {
+	sessions, err := s.repo.listSessions(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	others := make([]session, 0, len(sessions))
+	for _, v := range sessions {
+		if v.SID != currentSID {
+			others = append(others, v)
+		}
+	}
+	return s.revokeSessions(ctx, uid, others...)
+}
+
+// 查询当前用户的两步验证状态
+func (s *svc) mfaStatus(ctx context.Context, uid string) (*mfaStatusRes, error) {
+	m, err := s.loadMFA(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	required, err := s.roles.RequiresMFA(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &mfaStatusRes{Required: required}
+	if m != nil && m.Enabled {
+		res.Enabled = true
+		res.RecoveryCodes = len(m.RecoveryCodes)
+	}
+	return res, nil
+}
+
+// 生成待启用的 TOTP 密钥，需调用 mfaConfirm 校验一次验证码后才会生效
+func (s *svc) mfaSetup(ctx context.Context, uid string) (*mfaSetupRes, error) {
+	account, err := s.repo.findUserByUID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return s.generateMFASecret(ctx, account)
+}
+
+// 校验验证码并启用两步验证，返回恢复码
+func (s *svc) mfaConfirm(ctx context.Context, uid string, req *mfaCodeReq, cl *client) (*recoveryCodesRes, error) {
+	account, err := s.userGuard(ctx, uid, cl.IP)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := s.loadMFA(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	codes, err := s.enableMFA(ctx, m, req.Code)
+	if errors.Is(err, errMFACodeInvalid) {
+		return nil, s.mfaCodeFailed(ctx, normalizeUsername(account.Username), cl.IP)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &recoveryCodesRes{RecoveryCodes: codes}, nil
+}
+
+// 关闭两步验证：需校验验证码，所属角色强制两步验证时不可关闭
+func (s *svc) mfaDisable(ctx context.Context, uid string, req *mfaCodeReq, cl *client) error {
+	required, err := s.roles.RequiresMFA(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if required {
+		return errMFARequired
+	}
+
+	m, err := s.enabledMFA(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if err := s.verifyUserMFACode(ctx, m, req.Code, cl.IP); err != nil {
+		return err
+	}
+	return s.repo.deleteMFA(ctx, uid)
+}
+
+// 重新生成恢复码：需校验验证码，旧恢复码全部作废
+func (s *svc) mfaRecoveryCodes(ctx context.Context, uid string, req *mfaCodeReq, cl *client) (*recoveryCodesRes, error) {
+	m, err := s.enabledMFA(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifyUserMFACode(ctx, m, req.Code, cl.IP); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes(mfaRecoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.replaceRecoveryCodes(ctx, uid, hashes); err != nil {
+		return nil, err
+	}
+	return &recoveryCodesRes{RecoveryCodes: codes}, nil
+}
+
+// IsRevoked 判断 access token 是否已被吊销
+func (s *svc) IsRevoked(ctx context.Context, claims *jwt.Claims) (bool, error) {
+	return s.repo.isDenied(ctx, claims.UID, claims.ID, claims.IssuedAt.Time)
+}
+
+// RevokeUser 吊销用户已签发的全部 token 并下线其全部会话（用户被禁用、删除时调用）
+func (s *svc) RevokeUser(ctx context.Context, uid string) error {
+	// 1. 用户级吊销时间点：此前签发的 access token 全部失效
+	if err := s.repo.denyUserBefore(ctx, uid, time.Now(), s.jt.GetAccessExpire()); err != nil {
+		return err
+	}
+
+	// 2. 删除全部会话，refresh token 随之失效
+	sessions, err := s.repo.listSessions(ctx, uid)
+	if err != nil {
+		return err
+	}
+	sids := make([]string, 0, len(sessions))
+	for _, v := range sessions {
+		sids = append(sids, v.SID)
+	}
+	return s.repo.delSessions(ctx, uid, sids...)
+}
+
+// IsAvailable 判断账号是否可用（已启用且未删除）
+func (s *svc) IsAvailable(ctx context.Context, uid string) (bool, error) {
+	available, _, err := s.AccountState(ctx, uid)
+	return available, err
+}
+
+// AccountState 查询账号是否可用，以及是否须先修改密码
+func (s *svc) AccountState(ctx context.Context, uid string) (bool, bool, error) {
+	// 1. 优先读取缓存，缓存异常时降级查库
+	state, hit, err := s.repo.getStatusCache(ctx, uid)
+	if err != nil {
+		log.WarnContext(ctx, "读取账号状态缓存失败", "uid", uid, "error", err.Error())
+	}
+	if hit {
+		return state.Available, state.MustChangePassword, nil
+	}
+
+	// 2. 查库并回填缓存
+	state, err = s.repo.findUserState(ctx, uid)
+	if err != nil {
+		return false, false, err
+	}
+	if err := s.repo.setStatusCache(ctx, uid, state, statusCacheTTL); err != nil {
+		log.WarnContext(ctx, "写入账号状态缓存失败", "uid", uid, "error", err.Error())
+	}
+	return state.Available, state.MustChangePassword, nil
+}
+
+// InvalidateStatus 失效账号状态缓存
+func (s *svc) InvalidateStatus(ctx context.Context, uid string) error {
+	return s.repo.delStatusCache(ctx, uid)
+}
+
+// PurgeUsers 清理被彻底删除用户的认证数据；会话与令牌已在软删除时吊销，此时均已过期
+func (s *svc) PurgeUsers(ctx context.Context, uids []string) error {
+	if err := s.repo.deleteUserData(ctx, uids); err != nil {
+		return err
+	}
+	for _, uid := range uids {
+		if err := s.repo.delStatusCache(ctx, uid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revokeSessions 下线会话：吊销会话最近签发的 access token，并删除会话使 refresh token 失效
+func (s *svc) revokeSessions(ctx context.Context, uid string, sessions ...session) error {
+	if len(sessions) == 0 {
+		return nil
+	}
+
+	jtis := make(map[string]time.Duration, len(sessions))
+	sids := make([]string, 0, len(sessions))
+	for _, v := range sessions {
+		jtis[v.AccessJTI] = time.Until(v.AccessExpiresAt)
+		sids = append(sids, v.SID)
+	}
+
+	if err := s.repo.denyTokens(ctx, jtis); err != nil {
+		return err
+	}
+	return s.repo.delSessions(ctx, uid, sids...)
+}
+
+// 新建会话并签发 token 对；超出最大会话数时踢下最久未活跃的会话
+func (s *svc) createSession(ctx context.Context, uid string, cl *client) (*jwt.TokenPair, error) {
+
+	// 1. 会话数限制：为新会话腾出位置
+	if s.cfg.MaxSessions > 0 {
+		sessions, err := s.repo.listSessions(ctx, uid)
+		if err != nil {
+			return nil, err
+		}
+		if over := len(sessions) - s.cfg.MaxSessions + 1; over > 0 {
+			// listSessions 按最近活跃时间倒序，末尾即最久未活跃
+			if err := s.revokeSessions(ctx, uid, sessions[len(sessions)-over:]...); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// 2. 签发 token 对，claims 中携带会话标识
+	sid := uuid.NewUUID()
+	tokenPair, err := s.jt.GenerateTokenPair(uid, sid)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 保存会话，寿命与 refresh token 一致
+	now := time.Now()
+	sess := &session{
+		SID:        sid,
+		UID:        uid,
+		Device:     cl.Device,
+		IP:         cl.IP,
+		UserAgent:  cl.UserAgent,
+		TokenHash:  hashToken(tokenPair.RefreshToken),
+		CreatedAt:  now,
+		LastSeenAt: now,
+
+		AccessJTI:       tokenPair.AccessJTI,
+		AccessExpiresAt: time.Unix(tokenPair.ExpiresAt, 0),
+	}
+	if err := s.repo.setSession(ctx, sess, s.jt.GetRefreshExpire(), s.jt.GetRefreshExpire()); err != nil {
+		return nil, err
+	}
+
+	return tokenPair, nil
+}
+
+// 解析 refresh token 并加载其所属会话；会话不存在或 token 已被替换均视为无效（401）
+func (s *svc) loadSession(ctx context.Context, refreshToken string) (*jwt.Claims, *session, error) {
 	claims, err := s.jt.ParseToken(refreshToken, "refresh")
+	if err != nil {
+		return nil, nil, errs.Wrap(errs.KindUnauthorized, err)
+	}
+	if claims.SID == "" {
+		return nil, nil, errRefreshTokenInvalid
+	}
+
+	sess, err := s.repo.getSession(ctx, claims.UID, claims.SID)
+	if errors.Is(err, errSessionNotFound) {
+		return nil, nil, errs.Wrap(errs.KindUnauthorized, err)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 摘要不一致：说明出示的是本会话已轮换掉的旧 token
+	hash := hashToken(refreshToken)
+	if subtle.ConstantTimeCompare([]byte(sess.TokenHash), []byte(hash)) != 1 {
+		// 刚轮换过的上一个 token 在宽限期内出现，视为同一客户端的并发刷新，仅拒绝本次请求
+		if sess.PrevTokenHash == hash && time.Since(sess.RotatedAt) < refreshReuseGrace {
+			return nil, nil, errRefreshTokenInvalid
+		}
+
+		// 否则视为令牌被盗用后的重放：下线整个会话（token 家族），合法用户与攻击者都需重新登录
+		log.WarnContext(ctx, "检测到 refresh token 重放，下线会话", "uid", sess.UID, "sid", sess.SID)
+		if err := s.revokeSessions(ctx, sess.UID, *sess); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, errRefreshTokenReused
+	}
+	return claims, sess, nil
+}
+
+// newMFAChallenge 需要两步验证时签发挑战令牌，不需要时返回 nil
+func (s *svc) newMFAChallenge(ctx context.Context, uid, username string) (*mfaChallengeRes, error) {
+	m, err := s.loadMFA(ctx, uid)
 	if err != nil {
 		return nil, err
 	}
 
-	// 2. 计算剩余有效期 (实现绝对过期时间，防止无限续期)
-	remaining := time.Until(claims.ExpiresAt.Time)
-	if remaining <= 0 {
-		return nil, jwt.ErrExpiredToken
+	// 未启用两步验证：仅当所属角色强制要求时，引导用户在登录流程中完成绑定
+	setup := false
+	if m == nil || !m.Enabled {
+		required, err := s.roles.RequiresMFA(ctx, uid)
+		if err != nil {
+			return nil, err
+		}
+		if !required {
+			return nil, nil
+		}
+		setup = true
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	ch := &mfaChallenge{UID: uid, Username: username, Setup: setup}
+	if err := s.repo.setMFAChallenge(ctx, token, ch, mfaChallengeTTL); err != nil {
+		return nil, err
+	}
+	return &mfaChallengeRes{MFAToken: token, SetupRequired: setup}, nil
+}
+
+// generateMFASecret 生成并保存待启用的 TOTP 密钥（已启用时返回 errMFAAlreadyEnabled）
+func (s *svc) generateMFASecret(ctx context.Context, account *account) (*mfaSetupRes, error) {
+	issuer := s.cfg.MFAIssuer
+	if issuer == "" {
+		issuer = defaultMFAIssuer
+	}
+	key, err := generateTOTPKey(issuer, account.Username)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.saveMFASecret(ctx, account.UID, key.Secret()); err != nil {
+		return nil, err
+	}
+	return &mfaSetupRes{Secret: key.Secret(), OTPAuthURL: key.URL()}, nil
+}
+
+// enableMFA 校验 TOTP 验证码并启用两步验证，返回恢复码明文（仅此一次）
+func (s *svc) enableMFA(ctx context.Context, m *MFA, code string) ([]string, error) {
+	if m == nil {
+		return nil, errMFASetupRequired
+	}
+	if m.Enabled {
+		return nil, errMFAAlreadyEnabled
+	}
+
+	step, ok := validateTOTP(m.Secret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return nil, errMFACodeInvalid
+	}
+
+	codes, hashes, err := generateRecoveryCodes(mfaRecoveryCodeCount)
+	if err != nil {
+		return nil, err
 	}
+	if err := s.repo.enableMFA(ctx, m.UserUID, hashes, step); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
 
-	// 3. 从jwt中提取UID
-	uid := claims.UID
+// verifyMFACode 校验 6 位 TOTP 验证码（同一时间步只能使用一次）或恢复码（使用后作废）
+func (s *svc) verifyMFACode(ctx context.Context, m *MFA, code string) error {
+	code = strings.TrimSpace(code)
+	if isTOTPCode(code) {
+		step, ok := validateTOTP(m.Secret, code, time.Now())
+		if !ok {
+			return errMFACodeInvalid
+		}
+		used, err := s.repo.useMFAStep(ctx, m.UserUID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return errMFACodeInvalid
+		}
+		return nil
+	}
 
-	// 4. redis 值校验
-	savedRefreshToken, err2 := s.repo.getRefreshToken(ctx, uid)
-	if err2 != nil {
-		return nil, err2
+	ok, err := s.repo.consumeRecoveryCode(ctx, m.UserUID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errMFACodeInvalid
 	}
+	log.InfoContext(ctx, "使用恢复码完成两步验证", "uid", m.UserUID)
+	return nil
+}
 
-	// 5. redis 取出来的token和前端传的 refresh token进行对比
-	if savedRefreshToken != refreshToken {
-		return nil, errors.New("刷新token无效")
+// verifyUserMFACode 已登录用户的敏感操作校验验证码，错误次数计入登录失败计数，防止凭 access token 暴力枚举
+func (s *svc) verifyUserMFACode(ctx context.Context, m *MFA, code, ip string) error {
+	account, err := s.userGuard(ctx, m.UserUID, ip)
+	if err != nil {
+		return err
+	}
+	err = s.verifyMFACode(ctx, m, code)
+	if errors.Is(err, errMFACodeInvalid) {
+		return s.mfaCodeFailed(ctx, normalizeUsername(account.Username), ip)
 	}
+	return err
+}
 
-	// 6. 签发新的 Token 对
-	// Access Token 总是给满额有效期
-	newAccess, err := s.jt.GenerateToken(uid, "access", s.jt.GetAccessExpire())
+// userGuard 查询已登录用户并校验其未被登录锁定（敏感操作的凭证错误计入登录失败次数）
+func (s *svc) userGuard(ctx context.Context, uid, ip string) (*account, error) {
+	account, err := s.repo.findUserByUID(ctx, uid)
 	if err != nil {
 		return nil, err
 	}
-	// Refresh Token 继承旧 Token 的剩余寿命
-	newRefresh, err := s.jt.GenerateToken(uid, "refresh", remaining)
+
+	guard, err := s.repo.getLoginGuard(ctx, normalizeUsername(account.Username), ip)
 	if err != nil {
 		return nil, err
 	}
+	if guard.UserLocked || guard.IPLocked {
+		return nil, errLoginLocked
+	}
+	return account, nil
+}
+
+// mfaCodeFailed 验证码错误计入登录失败次数，达到阈值时返回 errLoginLocked
+func (s *svc) mfaCodeFailed(ctx context.Context, username, ip string) error {
+	if err := s.loginFailed(ctx, username, ip); !errors.Is(err, errLoginFailed) {
+		return err
+	}
+	return errMFACodeInvalid
+}
 
-	// 7. 更新 Redis
-	if err := s.repo.setRefreshToken(ctx, uid, newRefresh, remaining); err != nil {
+// loadMFA 查询两步验证信息，未绑定时返回 nil
+func (s *svc) loadMFA(ctx context.Context, uid string) (*MFA, error) {
+	m, err := s.repo.getMFA(ctx, uid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return m, err
+}
+
+// enabledMFA 查询已启用的两步验证信息，未启用时返回 errMFANotEnabled
+func (s *svc) enabledMFA(ctx context.Context, uid string) (*MFA, error) {
+	m, err := s.loadMFA(ctx, uid)
+	if err != nil {
 		return nil, err
 	}
+	if m == nil || !m.Enabled {
+		return nil, errMFANotEnabled
+	}
+	return m, nil
+}
+
+// normalizeUsername 统一用户名格式，用于查找用户并作为失败计数 / 锁定的 Key，避免通过大小写绕过
+func normalizeUsername(username string) string {
+	return identity.NormalizeUsername(username)
+}
+
+// newToken 生成不透明令牌（256 位随机数）：两步验证挑战令牌、找回密码重置令牌
+func newToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
 
-	// 8. 返回 签发的token信息
-	return &loginRes{
-		UID:         uid,
-		AccessToken: newAccess,
-		ExpiresAt:   time.Now().Add(s.jt.GetAccessExpire()).Unix(),
-	}, nil
+// hashToken 计算 token 的 SHA-256（十六进制），Redis 中只保存摘要
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// deviceOf 从 User-Agent 粗略识别设备名称，如 "Chrome / macOS"
+func deviceOf(ua string) string {
+	browser := "未知客户端"
+	switch {
+	case strings.Contains(ua, "Edg/"):
+		browser = "Edge"
+	case strings.Contains(ua, "OPR/"):
+		browser = "Opera"
+	case strings.Contains(ua, "Firefox/"):
+		browser = "Firefox"
+	case strings.Contains(ua, "Chrome/"):
+		browser = "Chrome"
+	case strings.Contains(ua, "Safari/"):
+		browser = "Safari"
+	}
+
+	platform := "未知系统"
+	switch {
+	case strings.Contains(ua, "Windows"):
+		platform = "Windows"
+	case strings.Contains(ua, "iPhone"), strings.Contains(ua, "iPad"):
+		platform = "iOS"
+	case strings.Contains(ua, "Android"):
+		platform = "Android"
+	case strings.Contains(ua, "Mac OS X"):
+		platform = "macOS"
+	case strings.Contains(ua, "Linux"):
+		platform = "Linux"
+	}
+
+	return browser + " / " + platform
 }
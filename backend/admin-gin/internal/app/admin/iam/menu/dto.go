@@ -0,0 +1,82 @@
+package menu
+
+// 【菜单树】响应体
+type menuRes struct {
+
+	/** 菜单 ID */
+	ID uint64 `json:"id"`
+
+	/** 父菜单 ID，0 表示顶级菜单 */
+	ParentID uint64 `json:"parent_id"`
+
+	/** 菜单名称 */
+	Name string `json:"name"`
+
+	/** 前端路由路径 */
+	Path string `json:"path"`
+
+	/** 图标名称 */
+	Icon string `json:"icon"`
+
+	/** 显示顺序 */
+	Sort int `json:"sort"`
+
+	/** 是否在导航中隐藏 */
+	Hidden bool `json:"hidden"`
+
+	/** 绑定的权限点标识 */
+	Permission string `json:"permission"`
+
+	/** 子菜单 */
+	Children []*menuRes `json:"children"`
+}
+
+// 【新增菜单】请求体
+type createReq struct {
+
+	// 父菜单 ID，0 表示顶级菜单
+	ParentID uint64 `json:"parent_id" binding:"omitempty" example:"0"`
+
+	// 菜单名称
+	Name string `json:"name" binding:"required,max=64" example:"用户管理"`
+
+	// 前端路由路径，目录节点可为空
+	Path string `json:"path" binding:"omitempty,max=255" example:"/admin/users"`
+
+	// 图标名称
+	Icon string `json:"icon" binding:"omitempty,max=64" example:"users"`
+
+	// 显示顺序
+	Sort int `json:"sort" binding:"omitempty,min=0"`
+
+	// 是否在导航中隐藏
+	Hidden bool `json:"hidden"`
+
+	// 绑定的权限点标识，为空表示登录即可见
+	Permission string `json:"permission" binding:"omitempty,max=128" example:"user:list"`
+}
+
+// 【修改菜单】请求体（指针字段用于区分 "不修改" 和 "修改为零值"）
+type updateReq struct {
+
+	// 父菜单 ID，0 表示移动为顶级菜单
+	ParentID *uint64 `json:"parent_id"`
+
+	// 菜单名称
+	Name string `json:"name" binding:"omitempty,max=64"`
+
+	// 前端路由路径
+	Path *string `json:"path" binding:"omitempty,max=255"`
+
+	// 图标名称
+	Icon *string `json:"icon" binding:"omitempty,max=64"`
+
+	// 显示顺序
+	Sort *int `json:"sort" binding:"omitempty,min=0"`
+
+	// 是否在导航中隐藏
+	Hidden *bool `json:"hidden"`
+
+	// 绑定的权限点标识，传空字符串表示解除绑定
+	Permission *string `json:"permission" binding:"omitempty,max=128"`
+}
@@ -0,0 +1,146 @@
+package menu
+
+import (
+	"strconv"
+
+	"mall-api/internal/pkg/errs"
+	pkghttp "mall-api/internal/pkg/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type handler struct {
+	se service
+}
+
+func newHandler(se service) *handler {
+	return &handler{se: se}
+}
+
+// parseID 解析路径参数中的菜单 ID
+func parseID(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		c.Error(errs.BadRequest("id 不合法"))
+		return 0, false
+	}
+	return id, true
+}
+
+// @Summary		获取当前用户菜单
+// @Description	按当前用户角色所拥有的权限点过滤后的菜单树，前端据此生成导航
+// @ID				myMenu
+// @Security		BearerAuth
+// @Tags			Menu
+// @Accept			json
+// @Produce		json
+// @Success		200	{object}	pkghttp.HttpResponse[[]menuRes]	"查询成功"
+// @Router			/admin/iam/menu/mine [get]
+func (h *handler) mine(c *gin.Context) {
+	res, err := h.se.mine(c.Request.Context(), c.GetString("uid"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, res)
+}
+
+// @Summary		获取菜单树
+// @Description	完整菜单树（含所有菜单），用于菜单管理
+// @ID				listMenu
+// @Security		BearerAuth
+// @Tags			Menu
+// @Accept			json
+// @Produce		json
+// @Success		200	{object}	pkghttp.HttpResponse[[]menuRes]	"查询成功"
+// @Router			/admin/iam/menu [get]
+func (h *handler) tree(c *gin.Context) {
+	res, err := h.se.tree(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, res)
+}
+
+// @Summary		新增菜单
+// @Description	parent_id 为 0 表示顶级菜单
+// @ID				createMenu
+// @Security		BearerAuth
+// @Tags			Menu
+// @Accept			json
+// @Produce		json
+// @Param			data	body		createReq					true	"菜单参数"
+// @Success		200		{object}	pkghttp.HttpResponse[Empty]	"新增成功"
+// @Router			/admin/iam/menu [post]
+func (h *handler) create(c *gin.Context) {
+	var req createReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkghttp.BindError(err))
+		return
+	}
+
+	if err := h.se.create(c.Request.Context(), &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, pkghttp.Empty{})
+}
+
+// @Summary		修改菜单
+// @Description	支持移动父节点（不能移动到自身或其子菜单下）
+// @ID				updateMenu
+// @Security		BearerAuth
+// @Tags			Menu
+// @Accept			json
+// @Produce		json
+// @Param			id		path		int							true	"菜单 ID"
+// @Param			data	body		updateReq					true	"菜单参数"
+// @Success		200		{object}	pkghttp.HttpResponse[Empty]	"修改成功"
+// @Router			/admin/iam/menu/{id} [put]
+func (h *handler) update(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req updateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkghttp.BindError(err))
+		return
+	}
+
+	if err := h.se.update(c.Request.Context(), id, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, pkghttp.Empty{})
+}
+
+// @Summary		删除菜单
+// @Description	存在子菜单时不可删除
+// @ID				deleteMenu
+// @Security		BearerAuth
+// @Tags			Menu
+// @Accept			json
+// @Produce		json
+// @Param			id	path		int							true	"菜单 ID"
+// @Success		200	{object}	pkghttp.HttpResponse[Empty]	"删除成功"
+// @Router			/admin/iam/menu/{id} [delete]
+func (h *handler) delete(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.se.delete(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, pkghttp.Empty{})
+}
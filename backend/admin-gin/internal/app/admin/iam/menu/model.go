@@ -0,0 +1,36 @@
+package menu
+
+import "time"
+
+// Menu 后台菜单（父子树形结构，由前端据此动态生成导航与路由）
+type Menu struct {
+	/** 自增主键 */
+	ID uint64 `gorm:"primaryKey;autoIncrement"`
+
+	/** 父菜单 ID，0 表示顶级菜单 */
+	ParentID uint64 `gorm:"default:0;index"`
+
+	/** 菜单名称（用于导航展示） */
+	Name string `gorm:"size:64;not null"`
+
+	/** 前端路由路径，目录节点可为空 示例：/admin/users */
+	Path string `gorm:"size:255"`
+
+	/** 图标名称 */
+	Icon string `gorm:"size:64"`
+
+	/** 显示顺序（同级内升序） */
+	Sort int `gorm:"default:0"`
+
+	/** 是否在导航中隐藏（隐藏的菜单仍会下发，用于详情页等路由注册） */
+	Hidden bool `gorm:"default:false"`
+
+	/** 绑定的权限点标识，为空表示登录即可见 示例：user:list */
+	Permission string `gorm:"size:128"`
+
+	/** 创建时间 */
+	CreatedAt time.Time
+
+	/** 更新时间 */
+	UpdatedAt time.Time
+}
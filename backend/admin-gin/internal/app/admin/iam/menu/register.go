@@ -0,0 +1,14 @@
+package menu
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func Register(rg *gin.RouterGroup, db *gorm.DB, perms permissionProvider) {
+	repo := newRepository(db)
+	svc := newService(repo, perms)
+	h := newHandler(svc)
+
+	registerRouter(rg, h)
+}
@@ -0,0 +1,60 @@
+package menu
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type repository interface {
+	listAll(ctx context.Context) ([]Menu, error)                         // 查询全部菜单（按 sort、id 升序）
+	findByID(ctx context.Context, id uint64) (*Menu, error)              // 按 ID 查找菜单
+	create(ctx context.Context, m *Menu) error                           // 新增菜单
+	update(ctx context.Context, id uint64, updates map[string]any) error // 按 ID 更新菜单（部分字段更新）
+	delete(ctx context.Context, id uint64) error                         // 按 ID 删除菜单
+	countChildren(ctx context.Context, id uint64) (int64, error)         // 统计子菜单数量
+}
+
+type repo struct {
+	db *gorm.DB
+}
+
+func newRepository(db *gorm.DB) repository {
+	return &repo{db: db}
+}
+
+func (r *repo) listAll(ctx context.Context) ([]Menu, error) {
+	var list []Menu
+	if err := r.db.WithContext(ctx).Order("sort ASC, id ASC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *repo) findByID(ctx context.Context, id uint64) (*Menu, error) {
+	var m Menu
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&m).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *repo) create(ctx context.Context, m *Menu) error {
+	return r.db.WithContext(ctx).Create(m).Error
+}
+
+func (r *repo) update(ctx context.Context, id uint64, updates map[string]any) error {
+	return r.db.WithContext(ctx).Model(&Menu{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *repo) delete(ctx context.Context, id uint64) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&Menu{}).Error
+}
+
+func (r *repo) countChildren(ctx context.Context, id uint64) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&Menu{}).Where("parent_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
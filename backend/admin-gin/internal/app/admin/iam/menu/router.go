@@ -0,0 +1,20 @@
+package menu
+
+import (
+	"mall-api/internal/app/admin/iam/role"
+	"mall-api/internal/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func registerRouter(r *gin.RouterGroup, handlers *handler) {
+	mg := r.Group("/iam/menu")
+	mg.Use(middleware.JWT())
+	{
+		mg.GET("/mine", handlers.mine) // 仅需登录：按当前用户权限过滤
+		mg.GET("", middleware.RequirePermission(role.PermMenuList), handlers.tree)
+		mg.POST("", middleware.RequirePermission(role.PermMenuCreate), handlers.create)
+		mg.PUT("/:id", middleware.RequirePermission(role.PermMenuUpdate), handlers.update)
+		mg.DELETE("/:id", middleware.RequirePermission(role.PermMenuDelete), handlers.delete)
+	}
+}
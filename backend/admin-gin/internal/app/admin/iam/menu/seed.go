@@ -0,0 +1,70 @@
+package menu
+
+import (
+	"context"
+	"time"
+
+	"mall-api/internal/app/admin/iam/role"
+
+	"gorm.io/gorm"
+)
+
+// builtinMenu 内置菜单定义
+type builtinMenu struct {
+	Name       string
+	Path       string
+	Icon       string
+	Sort       int
+	Permission string
+	Children   []builtinMenu
+}
+
+// builtinMenus 默认菜单树，路由与权限点对应已实现的后台接口
+var builtinMenus = []builtinMenu{
+	{Name: "系统管理", Icon: "setting", Sort: 100, Children: []builtinMenu{
+		{Name: "用户管理", Path: "/admin/users", Icon: "user", Sort: 1, Permission: role.PermUserList},
+		{Name: "用户回收站", Path: "/admin/users/recycle", Icon: "delete", Sort: 2, Permission: role.PermUserDelete},
+		{Name: "角色管理", Path: "/admin/roles", Icon: "team", Sort: 3, Permission: role.PermRoleList},
+		{Name: "权限点", Path: "/admin/permissions", Icon: "safety", Sort: 4, Permission: role.PermPermissionList},
+		{Name: "菜单管理", Path: "/admin/menus", Icon: "menu", Sort: 5, Permission: role.PermMenuList},
+		{Name: "日志级别", Path: "/admin/system/log-level", Icon: "file-text", Sort: 6, Permission: role.PermLogLevelList},
+	}},
+}
+
+// SeedBuiltin 写入默认菜单树（幂等，可重复执行）
+// 仅在菜单表为空时写入，避免覆盖运营人员在运行时对菜单的调整
+func SeedBuiltin(ctx context.Context, db *gorm.DB) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&Menu{}).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+		return createMenus(tx, 0, builtinMenus, time.Now())
+	})
+}
+
+// createMenus 按层级写入菜单，子菜单挂在刚写入的父菜单下
+func createMenus(tx *gorm.DB, parentID uint64, menus []builtinMenu, now time.Time) error {
+	for _, bm := range menus {
+		m := &Menu{
+			ParentID:   parentID,
+			Name:       bm.Name,
+			Path:       bm.Path,
+			Icon:       bm.Icon,
+			Sort:       bm.Sort,
+			Permission: bm.Permission,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		if err := tx.Create(m).Error; err != nil {
+			return err
+		}
+		if err := createMenus(tx, m.ID, bm.Children, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
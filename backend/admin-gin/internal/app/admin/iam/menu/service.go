@@ -0,0 +1,230 @@
+package menu
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"mall-api/internal/app/admin/iam/role"
+	"mall-api/internal/pkg/errs"
+
+	"gorm.io/gorm"
+)
+
+var (
+	errMenuNotFound   = errs.NotFound("菜单不存在")
+	errParentNotFound = errs.Validation("父菜单不存在")
+	errParentCycle    = errs.Validation("不能将菜单移动到自身或其子菜单下")
+	errHasChildren    = errs.Conflict("菜单下仍有子菜单，无法删除")
+)
+
+// permissionProvider 用户权限点查询能力（由 iam/role 模块实现，经 boot 注入）
+type permissionProvider interface {
+	PermissionsOf(ctx context.Context, uid string) ([]string, error)
+}
+
+type service interface {
+	tree(ctx context.Context) ([]*menuRes, error)                // 完整菜单树（管理端使用）
+	mine(ctx context.Context, uid string) ([]*menuRes, error)    // 当前用户可见的菜单树
+	create(ctx context.Context, req *createReq) error            // 新增菜单
+	update(ctx context.Context, id uint64, req *updateReq) error // 修改菜单
+	delete(ctx context.Context, id uint64) error                 // 删除菜单
+}
+
+type svc struct {
+	repo  repository
+	perms permissionProvider
+}
+
+func newService(repo repository, perms permissionProvider) service {
+	return &svc{repo: repo, perms: perms}
+}
+
+func (s *svc) tree(ctx context.Context) ([]*menuRes, error) {
+	menus, err := s.repo.listAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return buildTree(menus, func(*Menu) bool { return true }), nil
+}
+
+func (s *svc) mine(ctx context.Context, uid string) ([]*menuRes, error) {
+	// 1. 查询当前用户的权限点（来自其已启用的角色）
+	codes, err := s.perms.PermissionsOf(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	all := slices.Contains(codes, role.PermissionAll)
+
+	// 2. 查询全部菜单
+	menus, err := s.repo.listAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 按权限点过滤：未绑定权限点的菜单登录即可见
+	return buildTree(menus, func(m *Menu) bool {
+		return m.Permission == "" || all || slices.Contains(codes, m.Permission)
+	}), nil
+}
+
+func (s *svc) create(ctx context.Context, req *createReq) error {
+	if req.ParentID != 0 {
+		if _, err := s.findMenu(ctx, req.ParentID); err != nil {
+			if errors.Is(err, errMenuNotFound) {
+				return errParentNotFound
+			}
+			return err
+		}
+	}
+
+	now := time.Now()
+	return s.repo.create(ctx, &Menu{
+		ParentID:   req.ParentID,
+		Name:       strings.TrimSpace(req.Name),
+		Path:       strings.TrimSpace(req.Path),
+		Icon:       strings.TrimSpace(req.Icon),
+		Sort:       req.Sort,
+		Hidden:     req.Hidden,
+		Permission: strings.TrimSpace(req.Permission),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+}
+
+func (s *svc) update(ctx context.Context, id uint64, req *updateReq) error {
+	if _, err := s.findMenu(ctx, id); err != nil {
+		return err
+	}
+
+	// 更新字段（部分更新）
+	updates := map[string]any{}
+
+	if req.ParentID != nil {
+		if err := s.checkParent(ctx, id, *req.ParentID); err != nil {
+			return err
+		}
+		updates["parent_id"] = *req.ParentID
+	}
+	if name := strings.TrimSpace(req.Name); name != "" {
+		updates["name"] = name
+	}
+	if req.Path != nil {
+		updates["path"] = strings.TrimSpace(*req.Path)
+	}
+	if req.Icon != nil {
+		updates["icon"] = strings.TrimSpace(*req.Icon)
+	}
+	if req.Sort != nil {
+		updates["sort"] = *req.Sort
+	}
+	if req.Hidden != nil {
+		updates["hidden"] = *req.Hidden
+	}
+	if req.Permission != nil {
+		updates["permission"] = strings.TrimSpace(*req.Permission)
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	updates["updated_at"] = time.Now()
+	return s.repo.update(ctx, id, updates)
+}
+
+func (s *svc) delete(ctx context.Context, id uint64) error {
+	if _, err := s.findMenu(ctx, id); err != nil {
+		return err
+	}
+
+	count, err := s.repo.countChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errHasChildren
+	}
+
+	return s.repo.delete(ctx, id)
+}
+
+// ================================ 内部辅助函数 ===================================
+
+// findMenu 按 ID 查找菜单，不存在时统一返回 errMenuNotFound
+func (s *svc) findMenu(ctx context.Context, id uint64) (*Menu, error) {
+	m, err := s.repo.findByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errMenuNotFound
+	}
+	return m, err
+}
+
+// checkParent 校验新的父菜单存在，且不是自身或自身的子孙节点（防止成环）
+func (s *svc) checkParent(ctx context.Context, id, parentID uint64) error {
+	if parentID == 0 {
+		return nil
+	}
+	if parentID == id {
+		return errParentCycle
+	}
+
+	menus, err := s.repo.listAll(ctx)
+	if err != nil {
+		return err
+	}
+	parentOf := make(map[uint64]uint64, len(menus))
+	for _, m := range menus {
+		parentOf[m.ID] = m.ParentID
+	}
+	if _, ok := parentOf[parentID]; !ok {
+		return errParentNotFound
+	}
+
+	// 沿新父节点向上追溯，若经过自身则说明新父节点是自身的子孙节点
+	for cur := parentID; cur != 0; cur = parentOf[cur] {
+		if cur == id {
+			return errParentCycle
+		}
+	}
+	return nil
+}
+
+// buildTree 将扁平菜单组装为树，visible 决定节点本身是否可见：
+// 不可见节点连同其子树一并剔除；没有路由路径的目录节点在子菜单全部不可见时同样剔除
+func buildTree(menus []Menu, visible func(*Menu) bool) []*menuRes {
+	children := make(map[uint64][]*Menu, len(menus))
+	for i := range menus {
+		m := &menus[i]
+		children[m.ParentID] = append(children[m.ParentID], m)
+	}
+
+	var build func(parentID uint64) []*menuRes
+	build = func(parentID uint64) []*menuRes {
+		out := make([]*menuRes, 0, len(children[parentID]))
+		for _, m := range children[parentID] {
+			if !visible(m) {
+				continue
+			}
+			sub := build(m.ID)
+			if m.Path == "" && len(sub) == 0 && len(children[m.ID]) > 0 {
+				continue
+			}
+			out = append(out, &menuRes{
+				ID:         m.ID,
+				ParentID:   m.ParentID,
+				Name:       m.Name,
+				Path:       m.Path,
+				Icon:       m.Icon,
+				Sort:       m.Sort,
+				Hidden:     m.Hidden,
+				Permission: m.Permission,
+				Children:   sub,
+			})
+		}
+		return out
+	}
+	return build(0)
+}
@@ -0,0 +1,119 @@
+package role
+
+// ==========================================================
+// 内置角色：系统初始化时写入 role 表，运营人员可在运行时新增其他角色
+// ==========================================================
+
+const (
+	// CodeSuperAdmin 超级管理员
+	// 权限：上帝视角，拥有所有权限，不可被删除，通常只有 1-2 个
+	CodeSuperAdmin = "super_admin"
+
+	// CodeAdmin 普通管理员/店长
+	// 权限：仅次于超级管理员，管理日常运营，但不能修改系统底层配置
+	CodeAdmin = "admin"
+
+	// CodeProductManager 商品管理员
+	// 权限：商品上架/下架、编辑详情、库存预警、分类管理、品牌管理
+	CodeProductManager = "product_manager"
+
+	// CodeMarketing 营销/运营专员
+	// 权限：装修店铺首页、发布公告、创建优惠券、管理秒杀活动、广告位管理
+	CodeMarketing = "marketing"
+
+	// CodeOrderManager 订单/仓储专员
+	// 权限：查看待发货订单、打印面单、填写物流号、处理退货入库
+	// 注意：通常看不到订单的“成本价”或“利润”
+	CodeOrderManager = "order_manager"
+
+	// CodeCustomerService 客服专员
+	// 权限：查看订单详情、查看物流、回复工单
+	// 注意：敏感信息（手机号）需脱敏，通常只有只读权限
+	CodeCustomerService = "customer_service"
+
+	// CodeFinance 财务专员
+	// 权限：查看营收报表、资金流水、审核退款打款、开发票
+	CodeFinance = "finance"
+)
+
+// PermissionAll 通配权限点：拥有该权限点即视为拥有全部权限（仅授予超级管理员）
+const PermissionAll = "*"
+
+// 权限点：格式 "资源:动作"，与路由上声明的权限点一一对应
+const (
+	PermUserList   = "user:list"
+	PermUserCreate = "user:create"
+	PermUserUpdate = "user:update"
+	PermUserDelete = "user:delete"
+	PermUserUnlock = "user:unlock"
+
+	PermRoleList   = "role:list"
+	PermRoleCreate = "role:create"
+	PermRoleUpdate = "role:update"
+	PermRoleDelete = "role:delete"
+
+	PermPermissionList   = "permission:list"
+	PermPermissionCreate = "permission:create"
+	PermPermissionUpdate = "permission:update"
+	PermPermissionDelete = "permission:delete"
+
+	PermMenuList   = "menu:list"
+	PermMenuCreate = "menu:create"
+	PermMenuUpdate = "menu:update"
+	PermMenuDelete = "menu:delete"
+
+	PermLogLevelList   = "log_level:list"
+	PermLogLevelUpdate = "log_level:update"
+)
+
+// builtinRole 内置角色定义
+type builtinRole struct {
+	Code        string
+	Name        string
+	Sort        int
+	RequireMFA  bool // 是否强制两步验证
+	Permissions []string
+}
+
+// builtinRoles 内置角色及其默认权限点
+var builtinRoles = []builtinRole{
+	{Code: CodeSuperAdmin, Name: "超级管理员", Sort: 1, RequireMFA: true, Permissions: []string{PermissionAll}},
+	{Code: CodeAdmin, Name: "系统管理员", Sort: 2, Permissions: []string{
+		PermUserList, PermUserCreate, PermUserUpdate, PermUserDelete, PermUserUnlock,
+		PermRoleList, PermPermissionList, PermMenuList,
+	}},
+	{Code: CodeProductManager, Name: "商品管理员", Sort: 3},
+	{Code: CodeMarketing, Name: "营销运营", Sort: 4},
+	{Code: CodeOrderManager, Name: "订单/仓储", Sort: 5},
+	{Code: CodeCustomerService, Name: "客服专员", Sort: 6},
+	{Code: CodeFinance, Name: "财务专员", Sort: 7, RequireMFA: true},
+}
+
+// builtinPermissions 内置权限点
+var builtinPermissions = []Permission{
+	{Code: PermissionAll, Name: "全部权限", Module: "system"},
+
+	{Code: PermUserList, Name: "用户列表", Module: "user"},
+	{Code: PermUserCreate, Name: "新增用户", Module: "user"},
+	{Code: PermUserUpdate, Name: "编辑用户", Module: "user"},
+	{Code: PermUserDelete, Name: "删除用户", Module: "user"},
+	{Code: PermUserUnlock, Name: "解除登录锁定", Module: "user"},
+
+	{Code: PermRoleList, Name: "角色列表", Module: "role"},
+	{Code: PermRoleCreate, Name: "新增角色", Module: "role"},
+	{Code: PermRoleUpdate, Name: "编辑角色", Module: "role"},
+	{Code: PermRoleDelete, Name: "删除角色", Module: "role"},
+
+	{Code: PermPermissionList, Name: "权限点列表", Module: "permission"},
+	{Code: PermPermissionCreate, Name: "新增权限点", Module: "permission"},
+	{Code: PermPermissionUpdate, Name: "编辑权限点", Module: "permission"},
+	{Code: PermPermissionDelete, Name: "删除权限点", Module: "permission"},
+
+	{Code: PermMenuList, Name: "菜单列表", Module: "menu"},
+	{Code: PermMenuCreate, Name: "新增菜单", Module: "menu"},
+	{Code: PermMenuUpdate, Name: "编辑菜单", Module: "menu"},
+	{Code: PermMenuDelete, Name: "删除菜单", Module: "menu"},
+
+	{Code: PermLogLevelList, Name: "查看日志级别", Module: "system"},
+	{Code: PermLogLevelUpdate, Name: "修改日志级别", Module: "system"},
+}
@@ -0,0 +1,137 @@
+package role
+
+import (
+	"mall-api/internal/pkg/http"
+	"time"
+)
+
+// 【获取角色列表】查询参数
+type listReq struct {
+
+	// 分页请求结构体复用
+	http.HttpPageRequest
+
+	// 关键字：匹配 code/name
+	Keyword string `form:"keyword" binding:"omitempty"`
+}
+
+// 【获取角色列表】响应体
+type roleRes struct {
+
+	/** 角色标识 */
+	Code string `json:"code"`
+
+	/** 角色名称 */
+	Name string `json:"name"`
+
+	/** 显示顺序 */
+	Sort int `json:"sort"`
+
+	/** 是否内置角色 */
+	IsBuiltin bool `json:"is_builtin"`
+
+	/** 是否启用 */
+	IsActive bool `json:"is_active"`
+
+	/** 是否强制两步验证 */
+	RequireMFA bool `json:"require_mfa"`
+
+	/** 备注 */
+	Remark string `json:"remark"`
+
+	/** 创建时间 */
+	CreatedAt time.Time `json:"created_at"`
+
+	/** 更新时间 */
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// 【新增角色】请求体
+type createReq struct {
+
+	// 角色标识：小写字母、数字、下划线，创建后不可修改
+	Code string `json:"code" binding:"required,min=2,max=64" example:"warehouse"`
+
+	// 角色名称
+	Name string `json:"name" binding:"required,max=64" example:"仓库管理员"`
+
+	// 显示顺序
+	Sort int `json:"sort" binding:"omitempty,min=0"`
+
+	// 是否强制两步验证
+	RequireMFA bool `json:"require_mfa"`
+
+	// 备注
+	Remark string `json:"remark" binding:"omitempty,max=255"`
+}
+
+// 【修改角色】请求体
+type updateReq struct {
+
+	// 角色名称
+	Name string `json:"name" binding:"omitempty,max=64"`
+
+	// 显示顺序：使用指针区分 "不修改" 和 "修改为 0"
+	Sort *int `json:"sort" binding:"omitempty,min=0"`
+
+	// 使用指针，以便区分 "不修改" 和 "修改为禁用(false)"
+	IsActive *bool `json:"is_active"`
+
+	// 是否强制两步验证：使用指针区分 "不修改" 和 "关闭"
+	RequireMFA *bool `json:"require_mfa"`
+
+	// 备注：使用指针区分 "不修改" 和 "清空"
+	Remark *string `json:"remark" binding:"omitempty,max=255"`
+}
+
+// 【设置角色权限点】请求体（全量覆盖）
+type setPermissionsReq struct {
+
+	// 权限点标识列表，传空数组表示清空
+	Permissions []string `json:"permissions" binding:"omitempty,dive,required" example:"user:list"`
+}
+
+// 【权限点】响应体
+type permissionRes struct {
+
+	/** 权限点标识 */
+	Code string `json:"code"`
+
+	/** 权限点名称 */
+	Name string `json:"name"`
+
+	/** 所属模块 */
+	Module string `json:"module"`
+
+	/** 备注 */
+	Remark string `json:"remark"`
+}
+
+// 【新增权限点】请求体
+type createPermissionReq struct {
+
+	// 权限点标识，格式 "资源:动作"
+	Code string `json:"code" binding:"required,max=128" example:"product:list"`
+
+	// 权限点名称
+	Name string `json:"name" binding:"required,max=64" example:"商品列表"`
+
+	// 所属模块
+	Module string `json:"module" binding:"omitempty,max=64" example:"product"`
+
+	// 备注
+	Remark string `json:"remark" binding:"omitempty,max=255"`
+}
+
+// 【修改权限点】请求体
+type updatePermissionReq struct {
+
+	// 权限点名称
+	Name string `json:"name" binding:"omitempty,max=64"`
+
+	// 所属模块
+	Module string `json:"module" binding:"omitempty,max=64"`
+
+	// 备注：使用指针区分 "不修改" 和 "清空"
+	Remark *string `json:"remark" binding:"omitempty,max=255"`
+}
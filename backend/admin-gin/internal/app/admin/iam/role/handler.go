@@ -0,0 +1,310 @@
+package role
+
+import (
+	"strings"
+
+	"mall-api/internal/pkg/errs"
+	pkghttp "mall-api/internal/pkg/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type handler struct {
+	se service
+}
+
+func newHandler(se service) *handler {
+	return &handler{se: se}
+}
+
+// @Summary		获取角色列表
+// @Description	支持分页以及关键字查询
+// @ID				listRole
+// @Security		BearerAuth
+// @Tags			Role
+// @Accept			json
+// @Produce		json
+// @Param			params	query		listReq											true	"查询参数"
+// @Success		200		{object}	pkghttp.HttpResponse[pkghttp.PageRes[roleRes]]	"查询成功"
+// @Router			/admin/iam/role [get]
+func (h *handler) listRoles(c *gin.Context) {
+	var req listReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(pkghttp.BindError(err))
+		return
+	}
+
+	res, total, err := h.se.listRoles(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OKWithPage(c, pkghttp.PageRes[roleRes]{
+		List:  res,
+		Total: int64(total),
+		Page:  req.GetPage(),
+		Size:  req.GetPageSize(),
+	})
+}
+
+// @Summary		新增角色
+// @Description	角色标识创建后不可修改
+// @ID				createRole
+// @Security		BearerAuth
+// @Tags			Role
+// @Accept			json
+// @Produce		json
+// @Param			data	body		createReq					true	"角色参数"
+// @Success		200		{object}	pkghttp.HttpResponse[Empty]	"新增成功"
+// @Router			/admin/iam/role [post]
+func (h *handler) createRole(c *gin.Context) {
+	var req createReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkghttp.BindError(err))
+		return
+	}
+
+	if err := h.se.createRole(c.Request.Context(), &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, pkghttp.Empty{})
+}
+
+// @Summary		修改角色
+// @Description	支持修改名称、排序、启用状态、备注；超级管理员角色不可禁用、不可关闭两步验证要求（403）
+// @ID				updateRole
+// @Security		BearerAuth
+// @Tags			Role
+// @Accept			json
+// @Produce		json
+// @Param			code	path		string						true	"角色标识"
+// @Param			data	body		updateReq					true	"角色参数"
+// @Success		200		{object}	pkghttp.HttpResponse[Empty]	"修改成功"
+// @Router			/admin/iam/role/{code} [put]
+func (h *handler) updateRole(c *gin.Context) {
+	code := strings.TrimSpace(c.Param("code"))
+	if code == "" {
+		c.Error(errs.BadRequest("code 不能为空"))
+		return
+	}
+
+	var req updateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkghttp.BindError(err))
+		return
+	}
+
+	if err := h.se.updateRole(c.Request.Context(), code, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, pkghttp.Empty{})
+}
+
+// @Summary		删除角色
+// @Description	内置角色、仍有关联用户的角色不可删除
+// @ID				deleteRole
+// @Security		BearerAuth
+// @Tags			Role
+// @Accept			json
+// @Produce		json
+// @Param			code	path		string						true	"角色标识"
+// @Success		200		{object}	pkghttp.HttpResponse[Empty]	"删除成功"
+// @Router			/admin/iam/role/{code} [delete]
+func (h *handler) deleteRole(c *gin.Context) {
+	code := strings.TrimSpace(c.Param("code"))
+	if code == "" {
+		c.Error(errs.BadRequest("code 不能为空"))
+		return
+	}
+
+	if err := h.se.deleteRole(c.Request.Context(), code); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, pkghttp.Empty{})
+}
+
+// @Summary		获取角色权限点
+// @Description	返回角色已绑定的权限点标识
+// @ID				listRolePermission
+// @Security		BearerAuth
+// @Tags			Role
+// @Accept			json
+// @Produce		json
+// @Param			code	path		string							true	"角色标识"
+// @Success		200		{object}	pkghttp.HttpResponse[[]string]	"查询成功"
+// @Router			/admin/iam/role/{code}/permission [get]
+func (h *handler) rolePermissions(c *gin.Context) {
+	code := strings.TrimSpace(c.Param("code"))
+	if code == "" {
+		c.Error(errs.BadRequest("code 不能为空"))
+		return
+	}
+
+	res, err := h.se.rolePermissions(c.Request.Context(), code)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, res)
+}
+
+// @Summary		设置角色权限点
+// @Description	全量覆盖角色的权限点，传空数组表示清空
+// @Description	超级管理员角色的权限点不可修改，通配权限点 * 不可绑定到其他角色（403）
+// @ID				setRolePermission
+// @Security		BearerAuth
+// @Tags			Role
+// @Accept			json
+// @Produce		json
+// @Param			code	path		string						true	"角色标识"
+// @Param			data	body		setPermissionsReq			true	"权限点参数"
+// @Success		200		{object}	pkghttp.HttpResponse[Empty]	"设置成功"
+// @Router			/admin/iam/role/{code}/permission [put]
+func (h *handler) setRolePermissions(c *gin.Context) {
+	code := strings.TrimSpace(c.Param("code"))
+	if code == "" {
+		c.Error(errs.BadRequest("code 不能为空"))
+		return
+	}
+
+	var req setPermissionsReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkghttp.BindError(err))
+		return
+	}
+
+	if err := h.se.setRolePermissions(c.Request.Context(), code, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, pkghttp.Empty{})
+}
+
+// @Summary		获取权限点列表
+// @Description	返回全部权限点，按模块排序
+// @ID				listPermission
+// @Security		BearerAuth
+// @Tags			Permission
+// @Accept			json
+// @Produce		json
+// @Success		200	{object}	pkghttp.HttpResponse[[]permissionRes]	"查询成功"
+// @Router			/admin/iam/permission [get]
+func (h *handler) listPermissions(c *gin.Context) {
+	res, err := h.se.listPermissions(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, res)
+}
+
+// @Summary		新增权限点
+// @Description	权限点标识格式 "资源:动作"，需与路由声明保持一致
+// @ID				createPermission
+// @Security		BearerAuth
+// @Tags			Permission
+// @Accept			json
+// @Produce		json
+// @Param			data	body		createPermissionReq			true	"权限点参数"
+// @Success		200		{object}	pkghttp.HttpResponse[Empty]	"新增成功"
+// @Router			/admin/iam/permission [post]
+func (h *handler) createPermission(c *gin.Context) {
+	var req createPermissionReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkghttp.BindError(err))
+		return
+	}
+
+	if err := h.se.createPermission(c.Request.Context(), &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, pkghttp.Empty{})
+}
+
+// @Summary		修改权限点
+// @Description	支持修改名称、模块、备注
+// @ID				updatePermission
+// @Security		BearerAuth
+// @Tags			Permission
+// @Accept			json
+// @Produce		json
+// @Param			code	path		string						true	"权限点标识"
+// @Param			data	body		updatePermissionReq			true	"权限点参数"
+// @Success		200		{object}	pkghttp.HttpResponse[Empty]	"修改成功"
+// @Router			/admin/iam/permission/{code} [put]
+func (h *handler) updatePermission(c *gin.Context) {
+	code := strings.TrimSpace(c.Param("code"))
+	if code == "" {
+		c.Error(errs.BadRequest("code 不能为空"))
+		return
+	}
+
+	var req updatePermissionReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkghttp.BindError(err))
+		return
+	}
+
+	if err := h.se.updatePermission(c.Request.Context(), code, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, pkghttp.Empty{})
+}
+
+// @Summary		删除权限点
+// @Description	同时解除与所有角色的绑定
+// @ID				deletePermission
+// @Security		BearerAuth
+// @Tags			Permission
+// @Accept			json
+// @Produce		json
+// @Param			code	path		string						true	"权限点标识"
+// @Success		200		{object}	pkghttp.HttpResponse[Empty]	"删除成功"
+// @Router			/admin/iam/permission/{code} [delete]
+func (h *handler) deletePermission(c *gin.Context) {
+	code := strings.TrimSpace(c.Param("code"))
+	if code == "" {
+		c.Error(errs.BadRequest("code 不能为空"))
+		return
+	}
+
+	if err := h.se.deletePermission(c.Request.Context(), code); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, pkghttp.Empty{})
+}
+
+// @Summary		获取当前用户权限点
+// @Description	返回当前登录用户通过已启用角色获得的权限点，前端据此控制按钮等元素的显示
+// @ID				myPermission
+// @Security		BearerAuth
+// @Tags			Permission
+// @Accept			json
+// @Produce		json
+// @Success		200	{object}	pkghttp.HttpResponse[[]string]	"查询成功"
+// @Router			/admin/iam/permission/mine [get]
+func (h *handler) myPermissions(c *gin.Context) {
+	res, err := h.se.myPermissions(c.Request.Context(), c.GetString("uid"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, res)
+}
@@ -1,12 +1,84 @@
 package role
 
-// type Role struct {
-// 	gorm.Model
-// 	RoleName string `gorm:"type:varchar(64);not null;comment:角色名称"`
-// 	RoleKey  string `gorm:"type:varchar(64);uniqueIndex;not null;comment:角色代码"`
-// 	RoleSort int    `gorm:"type:int;default:0;comment:显示顺序"`
-// 	Status   int    `gorm:"type:smallint;default:1;comment:状态 1:正常 2:禁用"`
-// 	Remark   string `gorm:"type:varchar(255);comment:备注"`
-
-// 	// Menus []*SysMenu `gorm:"many2many:sys_role_menus;"`
-// }
+import "time"
+
+// Role 角色
+type Role struct {
+	/** 自增主键（数据库内部使用，不对外暴露） */
+	ID uint64 `gorm:"primaryKey;autoIncrement"`
+
+	/** 角色标识（对外使用的业务主键），创建后不可修改 示例：super_admin / finance */
+	Code string `gorm:"size:64;uniqueIndex;not null"`
+
+	/** 角色名称（用于前端展示） */
+	Name string `gorm:"size:64;not null"`
+
+	/** 显示顺序 */
+	Sort int `gorm:"default:0"`
+
+	/** 是否内置角色（内置角色不可删除） */
+	IsBuiltin bool `gorm:"default:false"`
+
+	/** 是否启用（禁用后该角色下的权限点不再生效） */
+	IsActive bool `gorm:"default:true"`
+
+	/** 是否强制两步验证（拥有该角色的用户必须绑定 TOTP 后才能登录） */
+	RequireMFA bool `gorm:"column:require_mfa;default:false"`
+
+	/** 备注 */
+	Remark string `gorm:"size:255"`
+
+	/** 创建时间 */
+	CreatedAt time.Time
+
+	/** 更新时间 */
+	UpdatedAt time.Time
+}
+
+// Permission 权限点
+type Permission struct {
+	/** 自增主键 */
+	ID uint64 `gorm:"primaryKey;autoIncrement"`
+
+	/** 权限点标识，格式 "资源:动作" 示例：user:list */
+	Code string `gorm:"size:128;uniqueIndex;not null"`
+
+	/** 权限点名称 */
+	Name string `gorm:"size:64;not null"`
+
+	/** 所属模块，便于前端分组展示 */
+	Module string `gorm:"size:64;index"`
+
+	/** 备注 */
+	Remark string `gorm:"size:255"`
+
+	/** 创建时间 */
+	CreatedAt time.Time
+
+	/** 更新时间 */
+	UpdatedAt time.Time
+}
+
+// UserRole 用户-角色中间表（中间表不使用双 ID）
+type UserRole struct {
+	/** 用户 UID */
+	UserUID string `gorm:"size:32;primaryKey"`
+
+	/** 角色 ID */
+	RoleID uint64 `gorm:"primaryKey;index"`
+
+	/** 创建时间 */
+	CreatedAt time.Time
+}
+
+// RolePermission 角色-权限点中间表（中间表不使用双 ID）
+type RolePermission struct {
+	/** 角色 ID */
+	RoleID uint64 `gorm:"primaryKey"`
+
+	/** 权限点 ID */
+	PermissionID uint64 `gorm:"primaryKey;index"`
+
+	/** 创建时间 */
+	CreatedAt time.Time
+}
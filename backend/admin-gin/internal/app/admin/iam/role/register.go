@@ -0,0 +1,27 @@
+package role
+
+import (
+	"mall-api/internal/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// log 模块日志，级别由配置 log.levels.role 控制（未配置时跟随全局级别）
+var log = logger.Component("role")
+
+// Register 模块自组装并注册路由，返回用户-角色绑定能力供其他模块注入使用
+func Register(rg *gin.RouterGroup, db *gorm.DB, rdb *redis.Client) UserRoles {
+	repo := newRepository(db, rdb)
+	svc := newService(repo)
+	h := newHandler(svc)
+
+	registerRouter(rg, h)
+	return svc
+}
+
+// NewUserRoles 仅组装用户-角色绑定能力，不注册路由（供 cmd/seed 等命令行工具使用）
+func NewUserRoles(db *gorm.DB, rdb *redis.Client) UserRoles {
+	return newService(newRepository(db, rdb))
+}
@@ -0,0 +1,390 @@
+package role
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+type repository interface {
+	// ========================= 角色 =========================
+	listRoles(ctx context.Context, page, size int, keyword string) ([]Role, int, error) // 分页查询角色列表，支持 keyword 模糊匹配（code/name）
+	findRoleByCode(ctx context.Context, code string) (*Role, error)                     // 按角色标识查找角色
+	findRolesByCodes(ctx context.Context, codes []string) ([]Role, error)               // 按角色标识批量查找角色
+	createRole(ctx context.Context, r *Role) error                                      // 新增角色
+	updateRole(ctx context.Context, id uint64, updates map[string]any) error            // 按 ID 更新角色（部分字段更新）
+	deleteRole(ctx context.Context, id uint64) error                                    // 删除角色及其权限点关联
+	countUsersOfRole(ctx context.Context, id uint64) (int64, error)                     // 统计角色下的用户数
+
+	// ========================= 权限点 =========================
+	listPermissions(ctx context.Context) ([]Permission, error)                               // 查询全部权限点
+	findPermissionByCode(ctx context.Context, code string) (*Permission, error)              // 按标识查找权限点
+	findPermissionsByCodes(ctx context.Context, codes []string) ([]Permission, error)        // 按标识批量查找权限点
+	createPermission(ctx context.Context, p *Permission) error                               // 新增权限点
+	updatePermission(ctx context.Context, id uint64, updates map[string]any) error           // 按 ID 更新权限点
+	deletePermission(ctx context.Context, id uint64) error                                   // 删除权限点及其角色关联
+	permissionCodesOfRole(ctx context.Context, roleID uint64) ([]string, error)              // 查询角色拥有的权限点标识
+	permissionCodesOfRoles(ctx context.Context, codes []string) ([]string, error)            // 查询多个角色（按标识）拥有的权限点标识（去重）
+	replaceRolePermissions(ctx context.Context, roleID uint64, permissionIDs []uint64) error // 全量覆盖角色的权限点
+	roleIDsOfPermission(ctx context.Context, permissionID uint64) ([]uint64, error)          // 查询绑定了某权限点的角色 ID
+	userUIDsOfRoles(ctx context.Context, roleIDs []uint64) ([]string, error)                 // 查询拥有任一角色的用户 UID
+
+	// ========================= 用户-角色 =========================
+	roleCodesOfUser(ctx context.Context, uid string) ([]string, error)                // 查询用户的角色标识
+	roleCodesOfUsers(ctx context.Context, uids []string) (map[string][]string, error) // 批量查询用户的角色标识
+	userUIDsOfRole(ctx context.Context, code string) ([]string, error)                // 查询拥有某角色的用户 UID
+	replaceUserRoles(ctx context.Context, uid string, roleIDs []uint64) error         // 全量覆盖用户的角色
+	deleteUserRoles(ctx context.Context, uids []string) error                         // 删除用户的全部角色关联（用户被彻底删除时）
+	permissionCodesOfUser(ctx context.Context, uid string) ([]string, error)          // 查询用户通过已启用角色获得的权限点标识
+	countMFARolesOfUser(ctx context.Context, uid string) (int64, error)               // 统计用户已启用且强制两步验证的角色数
+
+	// ========================= 权限缓存 =========================
+	getPermissionCache(ctx context.Context, uid string) ([]string, bool, error)                  // 读取用户权限点缓存，bool 表示是否命中
+	setPermissionCache(ctx context.Context, uid string, codes []string, ttl time.Duration) error // 写入用户权限点缓存
+	delPermissionCache(ctx context.Context, uids ...string) error                                // 删除用户权限点缓存（角色变更时失效）
+}
+
+type repo struct {
+	db  *gorm.DB
+	rdb *redis.Client
+}
+
+func newRepository(db *gorm.DB, rdb *redis.Client) repository {
+	return &repo{db: db, rdb: rdb}
+}
+
+func (r *repo) listRoles(ctx context.Context, page, size int, keyword string) ([]Role, int, error) {
+	q := r.db.WithContext(ctx).Model(&Role{})
+
+	keyword = strings.TrimSpace(keyword)
+	if keyword != "" {
+		like := "%" + keyword + "%"
+		q = q.Where("(code ILIKE ? OR name ILIKE ?)", like, like)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var list []Role
+	if err := q.Order("sort ASC, id ASC").
+		Offset((page - 1) * size).
+		Limit(size).
+		Find(&list).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return list, int(total), nil
+}
+
+func (r *repo) findRoleByCode(ctx context.Context, code string) (*Role, error) {
+	var m Role
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&m).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *repo) findRolesByCodes(ctx context.Context, codes []string) ([]Role, error) {
+	var list []Role
+	if len(codes) == 0 {
+		return list, nil
+	}
+	if err := r.db.WithContext(ctx).Where("code IN ?", codes).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *repo) createRole(ctx context.Context, m *Role) error {
+	return r.db.WithContext(ctx).Create(m).Error
+}
+
+func (r *repo) updateRole(ctx context.Context, id uint64, updates map[string]any) error {
+	return r.db.WithContext(ctx).Model(&Role{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *repo) deleteRole(ctx context.Context, id uint64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", id).Delete(&RolePermission{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&Role{}).Error
+	})
+}
+
+func (r *repo) countUsersOfRole(ctx context.Context, id uint64) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&UserRole{}).Where("role_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *repo) listPermissions(ctx context.Context) ([]Permission, error) {
+	var list []Permission
+	if err := r.db.WithContext(ctx).Order("module ASC, id ASC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *repo) findPermissionByCode(ctx context.Context, code string) (*Permission, error) {
+	var m Permission
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&m).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *repo) findPermissionsByCodes(ctx context.Context, codes []string) ([]Permission, error) {
+	var list []Permission
+	if len(codes) == 0 {
+		return list, nil
+	}
+	if err := r.db.WithContext(ctx).Where("code IN ?", codes).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *repo) createPermission(ctx context.Context, m *Permission) error {
+	return r.db.WithContext(ctx).Create(m).Error
+}
+
+func (r *repo) updatePermission(ctx context.Context, id uint64, updates map[string]any) error {
+	return r.db.WithContext(ctx).Model(&Permission{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *repo) deletePermission(ctx context.Context, id uint64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("permission_id = ?", id).Delete(&RolePermission{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&Permission{}).Error
+	})
+}
+
+func (r *repo) permissionCodesOfRole(ctx context.Context, roleID uint64) ([]string, error) {
+	var codes []string
+	err := r.db.WithContext(ctx).
+		Model(&Permission{}).
+		Joins("JOIN role_permission rp ON rp.permission_id = permission.id").
+		Where("rp.role_id = ?", roleID).
+		Order("permission.code ASC").
+		Pluck("permission.code", &codes).Error
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (r *repo) permissionCodesOfRoles(ctx context.Context, codes []string) ([]string, error) {
+	var out []string
+	if len(codes) == 0 {
+		return out, nil
+	}
+	err := r.db.WithContext(ctx).
+		Model(&Permission{}).
+		Distinct("permission.code").
+		Joins("JOIN role_permission rp ON rp.permission_id = permission.id").
+		Joins("JOIN role ON role.id = rp.role_id").
+		Where("role.code IN ?", codes).
+		Pluck("permission.code", &out).Error
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *repo) replaceRolePermissions(ctx context.Context, roleID uint64, permissionIDs []uint64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", roleID).Delete(&RolePermission{}).Error; err != nil {
+			return err
+		}
+		if len(permissionIDs) == 0 {
+			return nil
+		}
+
+		now := time.Now()
+		rows := make([]RolePermission, 0, len(permissionIDs))
+		for _, pid := range permissionIDs {
+			rows = append(rows, RolePermission{RoleID: roleID, PermissionID: pid, CreatedAt: now})
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+func (r *repo) roleIDsOfPermission(ctx context.Context, permissionID uint64) ([]uint64, error) {
+	var ids []uint64
+	if err := r.db.WithContext(ctx).Model(&RolePermission{}).Where("permission_id = ?", permissionID).Pluck("role_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *repo) userUIDsOfRoles(ctx context.Context, roleIDs []uint64) ([]string, error) {
+	var uids []string
+	if len(roleIDs) == 0 {
+		return uids, nil
+	}
+	if err := r.db.WithContext(ctx).Model(&UserRole{}).Where("role_id IN ?", roleIDs).Distinct().Pluck("user_uid", &uids).Error; err != nil {
+		return nil, err
+	}
+	return uids, nil
+}
+
+func (r *repo) roleCodesOfUser(ctx context.Context, uid string) ([]string, error) {
+	var codes []string
+	err := r.db.WithContext(ctx).
+		Model(&Role{}).
+		Joins("JOIN user_role ur ON ur.role_id = role.id").
+		Where("ur.user_uid = ?", uid).
+		Order("role.sort ASC").
+		Pluck("role.code", &codes).Error
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (r *repo) roleCodesOfUsers(ctx context.Context, uids []string) (map[string][]string, error) {
+	out := make(map[string][]string, len(uids))
+	if len(uids) == 0 {
+		return out, nil
+	}
+
+	var rows []struct {
+		UserUID string
+		Code    string
+	}
+	err := r.db.WithContext(ctx).
+		Model(&UserRole{}).
+		Select("user_role.user_uid, role.code").
+		Joins("JOIN role ON role.id = user_role.role_id").
+		Where("user_role.user_uid IN ?", uids).
+		Order("role.sort ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		out[row.UserUID] = append(out[row.UserUID], row.Code)
+	}
+	return out, nil
+}
+
+func (r *repo) userUIDsOfRole(ctx context.Context, code string) ([]string, error) {
+	var uids []string
+	err := r.db.WithContext(ctx).
+		Model(&UserRole{}).
+		Joins("JOIN role ON role.id = user_role.role_id").
+		Where("role.code = ?", code).
+		Pluck("user_role.user_uid", &uids).Error
+	if err != nil {
+		return nil, err
+	}
+	return uids, nil
+}
+
+func (r *repo) replaceUserRoles(ctx context.Context, uid string, roleIDs []uint64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_uid = ?", uid).Delete(&UserRole{}).Error; err != nil {
+			return err
+		}
+		if len(roleIDs) == 0 {
+			return nil
+		}
+
+		now := time.Now()
+		rows := make([]UserRole, 0, len(roleIDs))
+		for _, rid := range roleIDs {
+			rows = append(rows, UserRole{UserUID: uid, RoleID: rid, CreatedAt: now})
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+func (r *repo) deleteUserRoles(ctx context.Context, uids []string) error {
+	if len(uids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("user_uid IN ?", uids).Delete(&UserRole{}).Error
+}
+
+func (r *repo) permissionCodesOfUser(ctx context.Context, uid string) ([]string, error) {
+	var codes []string
+	err := r.db.WithContext(ctx).
+		Model(&Permission{}).
+		Distinct("permission.code").
+		Joins("JOIN role_permission rp ON rp.permission_id = permission.id").
+		Joins("JOIN role ON role.id = rp.role_id AND role.is_active = ?", true).
+		Joins("JOIN user_role ur ON ur.role_id = role.id").
+		Where("ur.user_uid = ?", uid).
+		Pluck("permission.code", &codes).Error
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (r *repo) countMFARolesOfUser(ctx context.Context, uid string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&Role{}).
+		Joins("JOIN user_role ur ON ur.role_id = role.id").
+		Where("ur.user_uid = ? AND role.is_active = ? AND role.require_mfa = ?", uid, true, true).
+		Count(&count).Error
+	return count, err
+}
+
+// 权限点缓存 Key 格式: "iam:perm:<uid>"，值为权限点标识的 JSON 数组（空数组同样缓存，避免穿透）
+func permissionCacheKey(uid string) string {
+	return fmt.Sprintf("iam:perm:%s", uid)
+}
+
+func (r *repo) getPermissionCache(ctx context.Context, uid string) ([]string, bool, error) {
+	val, err := r.rdb.Get(ctx, permissionCacheKey(uid)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil // 缓存未命中
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var codes []string
+	if err := json.Unmarshal([]byte(val), &codes); err != nil {
+		return nil, false, err
+	}
+	return codes, true, nil
+}
+
+func (r *repo) setPermissionCache(ctx context.Context, uid string, codes []string, ttl time.Duration) error {
+	if codes == nil {
+		codes = []string{}
+	}
+	val, err := json.Marshal(codes)
+	if err != nil {
+		return err
+	}
+	return r.rdb.Set(ctx, permissionCacheKey(uid), val, ttl).Err()
+}
+
+func (r *repo) delPermissionCache(ctx context.Context, uids ...string) error {
+	if len(uids) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(uids))
+	for _, uid := range uids {
+		keys = append(keys, permissionCacheKey(uid))
+	}
+	return r.rdb.Del(ctx, keys...).Err()
+}
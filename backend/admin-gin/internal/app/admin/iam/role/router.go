@@ -0,0 +1,33 @@
+package role
+
+import (
+	"mall-api/internal/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func registerRouter(r *gin.RouterGroup, handlers *handler) {
+
+	// 角色
+	rg := r.Group("/iam/role")
+	rg.Use(middleware.JWT())
+	{
+		rg.GET("", middleware.RequirePermission(PermRoleList), handlers.listRoles)
+		rg.POST("", middleware.RequirePermission(PermRoleCreate), handlers.createRole)
+		rg.PUT("/:code", middleware.RequirePermission(PermRoleUpdate), handlers.updateRole)
+		rg.DELETE("/:code", middleware.RequirePermission(PermRoleDelete), handlers.deleteRole)
+		rg.GET("/:code/permission", middleware.RequirePermission(PermRoleList), handlers.rolePermissions)
+		rg.PUT("/:code/permission", middleware.RequirePermission(PermRoleUpdate), handlers.setRolePermissions)
+	}
+
+	// 权限点
+	pg := r.Group("/iam/permission")
+	pg.Use(middleware.JWT())
+	{
+		pg.GET("/mine", handlers.myPermissions) // 仅需登录：当前用户查询自己的权限点
+		pg.GET("", middleware.RequirePermission(PermPermissionList), handlers.listPermissions)
+		pg.POST("", middleware.RequirePermission(PermPermissionCreate), handlers.createPermission)
+		pg.PUT("/:code", middleware.RequirePermission(PermPermissionUpdate), handlers.updatePermission)
+		pg.DELETE("/:code", middleware.RequirePermission(PermPermissionDelete), handlers.deletePermission)
+	}
+}
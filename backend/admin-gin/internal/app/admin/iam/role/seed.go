@@ -0,0 +1,92 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SeedBuiltin 写入内置权限点与内置角色（幂等，可重复执行）
+// 已存在的角色不会被修改，避免覆盖运营人员在运行时对内置角色权限的调整；
+// 本次新写入的内置权限点（如版本升级新增的 user:unlock）按默认定义绑定到已存在的内置角色
+func SeedBuiltin(ctx context.Context, db *gorm.DB) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		// 1. 内置权限点：标识冲突时跳过，记录本次新写入的权限点
+		codes := make([]string, 0, len(builtinPermissions))
+		for _, p := range builtinPermissions {
+			codes = append(codes, p.Code)
+		}
+		var existed []string
+		if err := tx.Model(&Permission{}).Where("code IN ?", codes).Pluck("code", &existed).Error; err != nil {
+			return err
+		}
+		perms := make([]Permission, 0, len(builtinPermissions))
+		added := make(map[string]bool, len(builtinPermissions))
+		for _, p := range builtinPermissions {
+			if slices.Contains(existed, p.Code) {
+				continue
+			}
+			p.CreatedAt, p.UpdatedAt = now, now
+			perms = append(perms, p)
+			added[p.Code] = true
+		}
+		if len(perms) > 0 {
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "code"}},
+				DoNothing: true,
+			}).Create(&perms).Error; err != nil {
+				return err
+			}
+		}
+
+		// 2. 内置角色：不存在时创建并绑定全部默认权限点；已存在时仅绑定本次新写入的默认权限点
+		for _, br := range builtinRoles {
+			bind := br.Permissions
+			var r Role
+			err := tx.Where("code = ?", br.Code).First(&r).Error
+			switch {
+			case err == nil:
+				bind = slices.DeleteFunc(slices.Clone(bind), func(code string) bool { return !added[code] })
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				r = Role{
+					Code:       br.Code,
+					Name:       br.Name,
+					Sort:       br.Sort,
+					IsBuiltin:  true,
+					IsActive:   true,
+					RequireMFA: br.RequireMFA,
+					CreatedAt:  now,
+					UpdatedAt:  now,
+				}
+				if err := tx.Create(&r).Error; err != nil {
+					return err
+				}
+			default:
+				return err
+			}
+
+			if len(bind) == 0 {
+				continue
+			}
+
+			var ids []uint64
+			if err := tx.Model(&Permission{}).Where("code IN ?", bind).Pluck("id", &ids).Error; err != nil {
+				return err
+			}
+			rows := make([]RolePermission, 0, len(ids))
+			for _, id := range ids {
+				rows = append(rows, RolePermission{RoleID: r.ID, PermissionID: id, CreatedAt: now})
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
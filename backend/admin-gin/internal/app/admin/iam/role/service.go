@@ -0,0 +1,469 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"mall-api/internal/pkg/errs"
+)
+
+var (
+	errRoleExists         = errs.Conflict("角色标识已存在")
+	errRoleCodeInvalid    = errs.Validation("角色标识只能包含小写字母、数字和下划线，且以字母开头")
+	errRoleBuiltin        = errs.Validation("内置角色不可删除")
+	errRoleInUse          = errs.Conflict("角色下仍有关联用户，无法删除")
+	errPermissionNotFound = errs.NotFound("权限点不存在")
+	errPermissionExists   = errs.Conflict("权限点标识已存在")
+	errPermissionBuiltin  = errs.Validation("通配权限点不可修改或删除")
+
+	// 提权保护：通配权限点仅属于超级管理员角色，超级管理员角色的权限点与启用状态不可修改（403）
+	errPermissionAllBind = errs.Forbidden("通配权限点仅授予超级管理员，不可绑定到其他角色")
+	errSuperAdminLocked  = errs.Forbidden("超级管理员角色的权限点、启用状态与两步验证要求不可修改")
+)
+
+// ErrRoleNotFound 角色不存在（分配角色时存在未定义的角色标识，同样返回该错误）
+var ErrRoleNotFound = errs.NotFound("角色不存在")
+
+// 角色标识格式：小写字母开头，只包含小写字母、数字、下划线
+var roleCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// permissionCacheTTL 用户权限点缓存有效期：角色变更时主动失效，TTL 仅作兜底
+const permissionCacheTTL = 10 * time.Minute
+
+// UserRoles 用户-角色绑定能力
+// 由 role 模块实现，通过 Register 返回给 boot，再注入到 user / auth 等模块，
+// 使用方在各自模块内按需定义更小的接口，不直接依赖 role 的仓储实现
+type UserRoles interface {
+	// RolesOf 查询用户的角色标识
+	RolesOf(ctx context.Context, uid string) ([]string, error)
+	// RolesOfUsers 批量查询用户的角色标识（用于列表展示）
+	RolesOfUsers(ctx context.Context, uids []string) (map[string][]string, error)
+	// UsersWithRole 查询拥有某角色的用户 UID
+	UsersWithRole(ctx context.Context, code string) ([]string, error)
+	// Exist 判断角色标识是否全部存在
+	Exist(ctx context.Context, codes []string) (bool, error)
+	// Assign 全量覆盖用户的角色，存在未定义的角色时返回 ErrRoleNotFound
+	Assign(ctx context.Context, uid string, codes []string) error
+	// UnassignUsers 删除用户的全部角色关联（用户被彻底删除时调用）
+	UnassignUsers(ctx context.Context, uids []string) error
+	// PermissionsOf 查询用户通过已启用角色获得的权限点标识（优先读取 Redis 缓存）
+	PermissionsOf(ctx context.Context, uid string) ([]string, error)
+	// PermissionsOfRoles 查询角色绑定的权限点标识（含未启用的角色，启用后即生效）
+	PermissionsOfRoles(ctx context.Context, codes []string) ([]string, error)
+	// HasPermission 判断用户是否拥有某权限点（拥有通配权限点 * 视为拥有全部权限）
+	HasPermission(ctx context.Context, uid string, code string) (bool, error)
+	// RequiresMFA 判断用户的已启用角色中是否存在强制两步验证的角色
+	RequiresMFA(ctx context.Context, uid string) (bool, error)
+}
+
+type service interface {
+	listRoles(ctx context.Context, req *listReq) ([]roleRes, int, error)               // 分页查询角色
+	createRole(ctx context.Context, req *createReq) error                              // 新增角色
+	updateRole(ctx context.Context, code string, req *updateReq) error                 // 修改角色
+	deleteRole(ctx context.Context, code string) error                                 // 删除角色
+	rolePermissions(ctx context.Context, code string) ([]string, error)                // 查询角色的权限点
+	setRolePermissions(ctx context.Context, code string, req *setPermissionsReq) error // 全量覆盖角色的权限点
+	listPermissions(ctx context.Context) ([]permissionRes, error)                      // 查询全部权限点
+	createPermission(ctx context.Context, req *createPermissionReq) error              // 新增权限点
+	updatePermission(ctx context.Context, code string, req *updatePermissionReq) error // 修改权限点
+	deletePermission(ctx context.Context, code string) error                           // 删除权限点
+	myPermissions(ctx context.Context, uid string) ([]string, error)                   // 查询当前用户的权限点
+}
+
+type svc struct {
+	repo repository
+}
+
+func newService(repo repository) *svc {
+	return &svc{repo: repo}
+}
+
+// ================================ 角色 ===================================
+
+func (s *svc) listRoles(ctx context.Context, req *listReq) ([]roleRes, int, error) {
+	roles, total, err := s.repo.listRoles(ctx, req.GetPage(), req.GetPageSize(), req.Keyword)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	out := make([]roleRes, 0, len(roles))
+	for _, r := range roles {
+		out = append(out, roleRes{
+			Code:       r.Code,
+			Name:       r.Name,
+			Sort:       r.Sort,
+			IsBuiltin:  r.IsBuiltin,
+			IsActive:   r.IsActive,
+			RequireMFA: r.RequireMFA,
+			Remark:     r.Remark,
+			CreatedAt:  r.CreatedAt,
+			UpdatedAt:  r.UpdatedAt,
+		})
+	}
+	return out, total, nil
+}
+
+func (s *svc) createRole(ctx context.Context, req *createReq) error {
+	code := strings.TrimSpace(req.Code)
+	if !roleCodePattern.MatchString(code) {
+		return errRoleCodeInvalid
+	}
+
+	// 角色标识唯一性检查
+	if _, err := s.repo.findRoleByCode(ctx, code); err == nil {
+		return errRoleExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	now := time.Now()
+	return s.repo.createRole(ctx, &Role{
+		Code:       code,
+		Name:       strings.TrimSpace(req.Name),
+		Sort:       req.Sort,
+		IsActive:   true,
+		RequireMFA: req.RequireMFA,
+		Remark:     strings.TrimSpace(req.Remark),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+}
+
+func (s *svc) updateRole(ctx context.Context, code string, req *updateReq) error {
+	r, err := s.findRole(ctx, code)
+	if err != nil {
+		return err
+	}
+	// 禁用超级管理员角色会使全部超级管理员失去权限；关闭其两步验证要求会静默降低最高权限角色的安全级别
+	if r.Code == CodeSuperAdmin && ((req.IsActive != nil && !*req.IsActive) || (req.RequireMFA != nil && !*req.RequireMFA)) {
+		return errSuperAdminLocked
+	}
+
+	// 更新字段（部分更新）
+	updates := map[string]any{}
+	if name := strings.TrimSpace(req.Name); name != "" {
+		updates["name"] = name
+	}
+	if req.Sort != nil {
+		updates["sort"] = *req.Sort
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
+	}
+	if req.RequireMFA != nil {
+		updates["require_mfa"] = *req.RequireMFA
+	}
+	if req.Remark != nil {
+		updates["remark"] = strings.TrimSpace(*req.Remark)
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	updates["updated_at"] = time.Now()
+	if err := s.repo.updateRole(ctx, r.ID, updates); err != nil {
+		return err
+	}
+
+	// 启用状态变化会影响该角色下所有用户的权限
+	if req.IsActive != nil && *req.IsActive != r.IsActive {
+		return s.invalidateRoles(ctx, r.ID)
+	}
+	return nil
+}
+
+func (s *svc) deleteRole(ctx context.Context, code string) error {
+	r, err := s.findRole(ctx, code)
+	if err != nil {
+		return err
+	}
+	if r.IsBuiltin {
+		return errRoleBuiltin
+	}
+
+	// 仍有用户关联时不允许删除，避免用户静默丢失权限
+	count, err := s.repo.countUsersOfRole(ctx, r.ID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errRoleInUse
+	}
+
+	return s.repo.deleteRole(ctx, r.ID)
+}
+
+func (s *svc) rolePermissions(ctx context.Context, code string) ([]string, error) {
+	r, err := s.findRole(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.permissionCodesOfRole(ctx, r.ID)
+}
+
+func (s *svc) setRolePermissions(ctx context.Context, code string, req *setPermissionsReq) error {
+	r, err := s.findRole(ctx, code)
+	if err != nil {
+		return err
+	}
+	if r.Code == CodeSuperAdmin {
+		return errSuperAdminLocked
+	}
+
+	codes := normalizeCodes(req.Permissions)
+	if slices.Contains(codes, PermissionAll) {
+		return errPermissionAllBind
+	}
+	perms, err := s.repo.findPermissionsByCodes(ctx, codes)
+	if err != nil {
+		return err
+	}
+	if len(perms) != len(codes) {
+		return errPermissionNotFound
+	}
+
+	ids := make([]uint64, 0, len(perms))
+	for _, p := range perms {
+		ids = append(ids, p.ID)
+	}
+	if err := s.repo.replaceRolePermissions(ctx, r.ID, ids); err != nil {
+		return err
+	}
+	return s.invalidateRoles(ctx, r.ID)
+}
+
+// ================================ 权限点 ===================================
+
+func (s *svc) listPermissions(ctx context.Context) ([]permissionRes, error) {
+	perms, err := s.repo.listPermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]permissionRes, 0, len(perms))
+	for _, p := range perms {
+		out = append(out, permissionRes{
+			Code:   p.Code,
+			Name:   p.Name,
+			Module: p.Module,
+			Remark: p.Remark,
+		})
+	}
+	return out, nil
+}
+
+func (s *svc) createPermission(ctx context.Context, req *createPermissionReq) error {
+	code := strings.TrimSpace(req.Code)
+	if code == PermissionAll {
+		return errPermissionExists
+	}
+
+	if _, err := s.repo.findPermissionByCode(ctx, code); err == nil {
+		return errPermissionExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	now := time.Now()
+	return s.repo.createPermission(ctx, &Permission{
+		Code:      code,
+		Name:      strings.TrimSpace(req.Name),
+		Module:    strings.TrimSpace(req.Module),
+		Remark:    strings.TrimSpace(req.Remark),
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+}
+
+func (s *svc) updatePermission(ctx context.Context, code string, req *updatePermissionReq) error {
+	p, err := s.findPermission(ctx, code)
+	if err != nil {
+		return err
+	}
+
+	updates := map[string]any{}
+	if name := strings.TrimSpace(req.Name); name != "" {
+		updates["name"] = name
+	}
+	if module := strings.TrimSpace(req.Module); module != "" {
+		updates["module"] = module
+	}
+	if req.Remark != nil {
+		updates["remark"] = strings.TrimSpace(*req.Remark)
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	updates["updated_at"] = time.Now()
+	return s.repo.updatePermission(ctx, p.ID, updates)
+}
+
+func (s *svc) deletePermission(ctx context.Context, code string) error {
+	p, err := s.findPermission(ctx, code)
+	if err != nil {
+		return err
+	}
+
+	// 删除前记录受影响的角色，删除后失效这些角色下用户的权限缓存
+	roleIDs, err := s.repo.roleIDsOfPermission(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.deletePermission(ctx, p.ID); err != nil {
+		return err
+	}
+	return s.invalidateRoles(ctx, roleIDs...)
+}
+
+func (s *svc) myPermissions(ctx context.Context, uid string) ([]string, error) {
+	return s.PermissionsOf(ctx, uid)
+}
+
+// ================================ UserRoles ===================================
+
+func (s *svc) RolesOf(ctx context.Context, uid string) ([]string, error) {
+	return s.repo.roleCodesOfUser(ctx, uid)
+}
+
+func (s *svc) RequiresMFA(ctx context.Context, uid string) (bool, error) {
+	count, err := s.repo.countMFARolesOfUser(ctx, uid)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (s *svc) RolesOfUsers(ctx context.Context, uids []string) (map[string][]string, error) {
+	return s.repo.roleCodesOfUsers(ctx, uids)
+}
+
+func (s *svc) UsersWithRole(ctx context.Context, code string) ([]string, error) {
+	return s.repo.userUIDsOfRole(ctx, strings.TrimSpace(code))
+}
+
+func (s *svc) Exist(ctx context.Context, codes []string) (bool, error) {
+	codes = normalizeCodes(codes)
+	roles, err := s.repo.findRolesByCodes(ctx, codes)
+	if err != nil {
+		return false, err
+	}
+	return len(roles) == len(codes), nil
+}
+
+func (s *svc) Assign(ctx context.Context, uid string, codes []string) error {
+	codes = normalizeCodes(codes)
+	roles, err := s.repo.findRolesByCodes(ctx, codes)
+	if err != nil {
+		return err
+	}
+	if len(roles) != len(codes) {
+		return ErrRoleNotFound
+	}
+
+	ids := make([]uint64, 0, len(roles))
+	for _, r := range roles {
+		ids = append(ids, r.ID)
+	}
+	if err := s.repo.replaceUserRoles(ctx, uid, ids); err != nil {
+		return err
+	}
+	return s.repo.delPermissionCache(ctx, uid)
+}
+
+func (s *svc) UnassignUsers(ctx context.Context, uids []string) error {
+	if len(uids) == 0 {
+		return nil
+	}
+	if err := s.repo.deleteUserRoles(ctx, uids); err != nil {
+		return err
+	}
+	return s.repo.delPermissionCache(ctx, uids...)
+}
+
+func (s *svc) PermissionsOf(ctx context.Context, uid string) ([]string, error) {
+	// 1. 优先读取缓存，缓存异常时降级查库
+	codes, hit, err := s.repo.getPermissionCache(ctx, uid)
+	if err != nil {
+		log.WarnContext(ctx, "读取权限缓存失败", "uid", uid, "error", err.Error())
+	}
+	if hit {
+		return codes, nil
+	}
+
+	// 2. 查库并回填缓存
+	codes, err = s.repo.permissionCodesOfUser(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.setPermissionCache(ctx, uid, codes, permissionCacheTTL); err != nil {
+		log.WarnContext(ctx, "写入权限缓存失败", "uid", uid, "error", err.Error())
+	}
+	return codes, nil
+}
+
+func (s *svc) PermissionsOfRoles(ctx context.Context, codes []string) ([]string, error) {
+	return s.repo.permissionCodesOfRoles(ctx, normalizeCodes(codes))
+}
+
+func (s *svc) HasPermission(ctx context.Context, uid string, code string) (bool, error) {
+	codes, err := s.PermissionsOf(ctx, uid)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(codes, PermissionAll) || slices.Contains(codes, code), nil
+}
+
+// ================================ 内部辅助函数 ===================================
+
+// findRole 按标识查找角色，不存在时统一返回 ErrRoleNotFound
+func (s *svc) findRole(ctx context.Context, code string) (*Role, error) {
+	r, err := s.repo.findRoleByCode(ctx, strings.TrimSpace(code))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRoleNotFound
+	}
+	return r, err
+}
+
+// findPermission 按标识查找权限点，不存在时统一返回 errPermissionNotFound；通配权限点不允许修改
+func (s *svc) findPermission(ctx context.Context, code string) (*Permission, error) {
+	code = strings.TrimSpace(code)
+	if code == PermissionAll {
+		return nil, errPermissionBuiltin
+	}
+
+	p, err := s.repo.findPermissionByCode(ctx, code)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errPermissionNotFound
+	}
+	return p, err
+}
+
+// invalidateRoles 失效拥有这些角色的用户的权限缓存
+func (s *svc) invalidateRoles(ctx context.Context, roleIDs ...uint64) error {
+	uids, err := s.repo.userUIDsOfRoles(ctx, roleIDs)
+	if err != nil {
+		return err
+	}
+	return s.repo.delPermissionCache(ctx, uids...)
+}
+
+// normalizeCodes 去除首尾空白、空值与重复值
+func normalizeCodes(codes []string) []string {
+	out := make([]string, 0, len(codes))
+	for _, c := range codes {
+		c = strings.TrimSpace(c)
+		if c != "" && !slices.Contains(out, c) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,58 @@
+package identity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// User 后台用户（user 表）
+type User struct {
+	/** 自增主键（数据库内部使用，不对外暴露） */
+	ID uint64 `gorm:"primaryKey;autoIncrement"`
+
+	/** 全局唯一用户标识（对外使用）用于安全、日志、审计等，不暴露真实数据库 ID */
+	UID string `gorm:"size:32;uniqueIndex"`
+
+	/** 登录用户名，保留原始大小写（部分唯一索引 idx_user_username_lower_alive：LOWER(username) 仅在未删除用户中唯一，删除后用户名可被复用） */
+	Username string `gorm:"size:64;not null"`
+
+	/** 邮箱（可选）可用于找回密码、内部通知等，统一以小写存储（部分唯一索引 idx_user_email_lower_alive：LOWER(email) 仅在未删除且已填写的用户中唯一） */
+	Email string `gorm:"size:128"`
+
+	/** 密码哈希值（bcrypt 加密）绝不存储明文密码 */
+	Password string `gorm:"size:255;not null"`
+
+	/** 是否启用账号（控制能否登录）*/
+	IsActive bool `gorm:"default:true"`
+
+	/** 下次登录须修改密码（管理员创建或重置密码后设置，修改密码后自动清除） */
+	MustChangePassword bool `gorm:"default:false"`
+
+	/** 创建时间 */
+	CreatedAt time.Time
+
+	/** 更新时间 */
+	UpdatedAt time.Time
+
+	/** 删除时间（软删除）：非空表示已删除，默认查询自动排除；超过回收站保留期后被彻底删除 */
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+// PasswordHistory 用户历史密码（仅保存 bcrypt hash），修改密码时用于拒绝复用最近使用过的密码
+type PasswordHistory struct {
+	/** 自增主键 */
+	ID uint64 `gorm:"primaryKey;autoIncrement"`
+
+	/** 用户 UID */
+	UserUID string `gorm:"size:32;index;not null"`
+
+	/** 曾经使用过的密码 hash（bcrypt） */
+	Password string `gorm:"size:255;not null"`
+
+	/** 记录时间（即该密码被替换的时间） */
+	CreatedAt time.Time
+}
+
+// TableName 历史密码使用 user_password_history 表
+func (PasswordHistory) TableName() string { return "user_password_history" }
@@ -0,0 +1,276 @@
+package identity
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Store 用户表（含历史密码）唯一的数据访问入口
+// auth（登录、注册、找回密码）与 admin/user（用户管理）各自定义所需的接口，由 Store 统一实现，同一张表只保留一份映射
+type Store struct {
+	db *gorm.DB
+}
+
+func NewStore(db *gorm.DB) *Store {
+	return &Store{db: db}
+}
+
+// NormalizeEmail 邮箱统一去除首尾空白并转为小写后写入与查询（唯一索引建立在 LOWER(email) 上）
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormalizeUsername 用户名去除首尾空白并转为小写后查询（唯一索引建立在 LOWER(username) 上，存储时保留原始大小写）
+func NormalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// baseQuery 未删除用户（gorm.DeletedAt 自动追加 deleted_at IS NULL）
+func (s *Store) baseQuery(ctx context.Context) *gorm.DB {
+	return s.db.WithContext(ctx).Model(&User{})
+}
+
+// deletedQuery 已删除用户（回收站）
+func (s *Store) deletedQuery(ctx context.Context) *gorm.DB {
+	return s.db.WithContext(ctx).Unscoped().Model(&User{}).Where("deleted_at IS NOT NULL")
+}
+
+// List 分页查询未删除用户，uids 非 nil 时限定范围，keyword 模糊匹配 uid/username/email
+func (s *Store) List(ctx context.Context, page, size int, uids []string, keyword string) ([]User, int, error) {
+	q := s.baseQuery(ctx)
+
+	if uids != nil {
+		q = q.Where("uid IN ?", uids)
+	}
+	return paginate(q, page, size, keyword, "created_at DESC")
+}
+
+// ListDeleted 分页查询已删除用户（回收站），按删除时间倒序
+func (s *Store) ListDeleted(ctx context.Context, page, size int, keyword string) ([]User, int, error) {
+	return paginate(s.deletedQuery(ctx), page, size, keyword, "deleted_at DESC")
+}
+
+// paginate 按 keyword 模糊匹配（uid/username/email）并分页
+func paginate(q *gorm.DB, page, size int, keyword, order string) ([]User, int, error) {
+	keyword = strings.TrimSpace(keyword)
+	if keyword != "" {
+		like := "%" + keyword + "%"
+		q = q.Where("(uid ILIKE ? OR username ILIKE ? OR email ILIKE ?)", like, like, like)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var list []User
+	if err := q.Order(order).
+		Offset((page - 1) * size).
+		Limit(size).
+		Find(&list).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return list, int(total), nil
+}
+
+// Create 新增用户（邮箱统一转为小写）
+func (s *Store) Create(ctx context.Context, u *User) error {
+	u.Email = NormalizeEmail(u.Email)
+	return s.db.WithContext(ctx).Create(u).Error
+}
+
+// GetByUID 按 UID 获取未删除的用户，不存在时返回 gorm.ErrRecordNotFound
+func (s *Store) GetByUID(ctx context.Context, uid string) (*User, error) {
+	var u User
+	if err := s.db.WithContext(ctx).Where("uid = ?", uid).First(&u).Error; err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// GetByUsername 按用户名获取未删除的用户，用户名忽略大小写
+func (s *Store) GetByUsername(ctx context.Context, username string) (*User, error) {
+	var u User
+	if err := s.db.WithContext(ctx).Where("LOWER(username) = ?", NormalizeUsername(username)).First(&u).Error; err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// GetByEmail 按邮箱获取未删除的用户，邮箱忽略大小写
+func (s *Store) GetByEmail(ctx context.Context, email string) (*User, error) {
+	var u User
+	if err := s.db.WithContext(ctx).Where("LOWER(email) = ?", NormalizeEmail(email)).First(&u).Error; err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// GetDeletedByUID 按 UID 获取已删除的用户
+func (s *Store) GetDeletedByUID(ctx context.Context, uid string) (*User, error) {
+	var u User
+	if err := s.deletedQuery(ctx).Where("uid = ?", uid).First(&u).Error; err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// UpdateByUID 按 UID 部分字段更新
+func (s *Store) UpdateByUID(ctx context.Context, uid string, updates map[string]any) error {
+	return s.db.WithContext(ctx).Model(&User{}).Where("uid = ?", uid).Updates(updates).Error
+}
+
+// SoftDeleteByUID 软删除（写入 deleted_at）
+func (s *Store) SoftDeleteByUID(ctx context.Context, uid string) error {
+	return s.db.WithContext(ctx).Where("uid = ?", uid).Delete(&User{}).Error
+}
+
+// RestoreByUID 恢复已删除的用户（清空 deleted_at），返回是否恢复成功
+func (s *Store) RestoreByUID(ctx context.Context, uid string) (bool, error) {
+	res := s.deletedQuery(ctx).Where("uid = ?", uid).Updates(map[string]any{
+		"deleted_at": nil,
+		"updated_at": time.Now(),
+	})
+	return res.RowsAffected > 0, res.Error
+}
+
+// DeletedBefore 查询删除时间早于 before 的用户 UID（最多 limit 个）
+func (s *Store) DeletedBefore(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	var uids []string
+	err := s.deletedQuery(ctx).
+		Where("deleted_at < ?", before).
+		Order("deleted_at").
+		Limit(limit).
+		Pluck("uid", &uids).Error
+	return uids, err
+}
+
+// PurgeByUIDs 彻底删除已软删除的用户及其历史密码，返回删除的用户数量
+func (s *Store) PurgeByUIDs(ctx context.Context, uids []string) (int, error) {
+	if len(uids) == 0 {
+		return 0, nil
+	}
+	var n int
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Unscoped().Where("uid IN ? AND deleted_at IS NOT NULL", uids).Delete(&User{})
+		if res.Error != nil {
+			return res.Error
+		}
+		n = int(res.RowsAffected)
+		return tx.Where("user_uid IN ?", uids).Delete(&PasswordHistory{}).Error
+	})
+	return n, err
+}
+
+// CountActive 统计 uids 中已启用且未删除的用户数量
+func (s *Store) CountActive(ctx context.Context, uids []string) (int, error) {
+	if len(uids) == 0 {
+		return 0, nil
+	}
+
+	var count int64
+	if err := s.baseQuery(ctx).Where("uid IN ? AND is_active = ?", uids, true).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// ExistsByUsername 判断用户名是否被未删除的用户占用（忽略大小写）
+func (s *Store) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+	var count int64
+	if err := s.baseQuery(ctx).Where("LOWER(username) = ?", NormalizeUsername(username)).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ExistsByEmail 判断邮箱是否被未删除的用户占用（忽略大小写）
+func (s *Store) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	email = NormalizeEmail(email)
+	if email == "" {
+		return false, nil
+	}
+
+	var count int64
+	if err := s.baseQuery(ctx).Where("LOWER(email) = ?", email).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ExistsByEmailExcludeUID 判断邮箱是否被其他未删除的用户占用（更新时校验，忽略大小写）
+func (s *Store) ExistsByEmailExcludeUID(ctx context.Context, email, excludeUID string) (bool, error) {
+	email = NormalizeEmail(email)
+	if email == "" {
+		return false, nil
+	}
+
+	var count int64
+	if err := s.baseQuery(ctx).Where("LOWER(email) = ? AND uid <> ?", email, excludeUID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// PasswordHistory 查询最近 limit 个历史密码 hash（按记录时间倒序）
+func (s *Store) PasswordHistory(ctx context.Context, uid string, limit int) ([]string, error) {
+	var hashes []string
+	if limit <= 0 {
+		return hashes, nil
+	}
+	err := s.db.WithContext(ctx).
+		Model(&PasswordHistory{}).
+		Where("user_uid = ?", uid).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Pluck("password", &hashes).Error
+	return hashes, err
+}
+
+// ChangePassword 修改密码：同一事务内更新密码、清除强制修改标记，并将旧密码写入历史，只保留最近 keep 个
+func (s *Store) ChangePassword(ctx context.Context, uid, hash, oldHash string, keep int) error {
+	return s.setPassword(ctx, uid, hash, oldHash, keep, false)
+}
+
+// ResetPassword 管理员重置密码：同一事务内更新密码、设置强制修改标记，并将旧密码写入历史，只保留最近 keep 个
+func (s *Store) ResetPassword(ctx context.Context, uid, hash, oldHash string, keep int) error {
+	return s.setPassword(ctx, uid, hash, oldHash, keep, true)
+}
+
+func (s *Store) setPassword(ctx context.Context, uid, hash, oldHash string, keep int, mustChange bool) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(&User{}).Where("uid = ?", uid).Updates(map[string]any{
+			"password":             hash,
+			"must_change_password": mustChange,
+			"updated_at":           now,
+		}).Error; err != nil {
+			return err
+		}
+
+		if keep <= 0 {
+			return tx.Where("user_uid = ?", uid).Delete(&PasswordHistory{}).Error
+		}
+		if err := tx.Create(&PasswordHistory{UserUID: uid, Password: oldHash, CreatedAt: now}).Error; err != nil {
+			return err
+		}
+
+		// 清理超出保留数量的旧记录
+		var stale []uint64
+		if err := tx.Model(&PasswordHistory{}).
+			Where("user_uid = ?", uid).
+			Order("created_at DESC, id DESC").
+			Offset(keep).
+			Pluck("id", &stale).Error; err != nil {
+			return err
+		}
+		if len(stale) == 0 {
+			return nil
+		}
+		return tx.Where("id IN ?", stale).Delete(&PasswordHistory{}).Error
+	})
+}
@@ -0,0 +1,34 @@
+package system
+
+// 【日志级别】响应体
+type logLevelRes struct {
+
+	/** 全局默认级别 */
+	Default string `json:"default" example:"info"`
+
+	/** 各组件的生效级别 */
+	Components []componentLevelRes `json:"components"`
+}
+
+// 组件日志级别
+type componentLevelRes struct {
+
+	/** 组件名称，与配置 log.levels 的 key 对应 */
+	Name string `json:"name" example:"gorm"`
+
+	/** 生效级别 */
+	Level string `json:"level" example:"warn"`
+
+	/** 是否单独设置了级别，false 表示跟随全局默认级别 */
+	Overridden bool `json:"overridden"`
+}
+
+// 【修改日志级别】请求体
+type setLogLevelReq struct {
+
+	// 组件名称，为空或 default 表示全局默认级别
+	Component string `json:"component" binding:"omitempty,max=32" example:"gorm"`
+
+	// 日志级别：debug / info / warn / error；为空表示移除组件的单独设置，恢复跟随全局默认级别
+	Level string `json:"level" binding:"omitempty,oneof=debug info warn error" example:"debug"`
+}
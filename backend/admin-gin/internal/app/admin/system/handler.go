@@ -0,0 +1,54 @@
+package system
+
+import (
+	pkghttp "mall-api/internal/pkg/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type handler struct {
+	se service
+}
+
+func newHandler(se service) *handler {
+	return &handler{se: se}
+}
+
+// @Summary		查询日志级别
+// @Description	返回全局默认级别与各组件（如 gorm、gin、auth）的生效级别
+// @ID				logLevels
+// @Security		BearerAuth
+// @Tags			System
+// @Accept			json
+// @Produce		json
+// @Success		200	{object}	pkghttp.HttpResponse[logLevelRes]	"查询成功"
+// @Router			/admin/system/log-level [get]
+func (h *handler) logLevels(c *gin.Context) {
+	pkghttp.OK(c, h.se.logLevels(c.Request.Context()))
+}
+
+// @Summary		修改日志级别
+// @Description	运行时修改全局默认级别或单个组件的级别，立即生效，无需重启；level 为空时移除组件的单独设置
+// @Description	仅修改处理该请求的实例，重启后恢复为配置文件中的级别
+// @ID				setLogLevel
+// @Security		BearerAuth
+// @Tags			System
+// @Accept			json
+// @Produce		json
+// @Param			data	body		setLogLevelReq						true	"级别参数"
+// @Success		200		{object}	pkghttp.HttpResponse[logLevelRes]	"修改成功，返回修改后的级别"
+// @Router			/admin/system/log-level [put]
+func (h *handler) setLogLevel(c *gin.Context) {
+	var req setLogLevelReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkghttp.BindError(err))
+		return
+	}
+
+	if err := h.se.setLogLevel(c.Request.Context(), c.GetString("uid"), &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, h.se.logLevels(c.Request.Context()))
+}
@@ -0,0 +1,17 @@
+package system
+
+import (
+	"mall-api/internal/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// log 模块日志，级别由配置 log.levels.system 控制（未配置时跟随全局级别）
+var log = logger.Component("system")
+
+// Register 系统运维接口：运行时日志级别
+func Register(rg *gin.RouterGroup) {
+	h := newHandler(newService())
+
+	registerRouter(rg, h)
+}
@@ -0,0 +1,17 @@
+package system
+
+import (
+	"mall-api/internal/app/admin/iam/role"
+	"mall-api/internal/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func registerRouter(r *gin.RouterGroup, handlers *handler) {
+	sg := r.Group("/system")
+	sg.Use(middleware.JWT())
+	{
+		sg.GET("/log-level", middleware.RequirePermission(role.PermLogLevelList), handlers.logLevels)
+		sg.PUT("/log-level", middleware.RequirePermission(role.PermLogLevelUpdate), handlers.setLogLevel)
+	}
+}
@@ -0,0 +1,64 @@
+package system
+
+import (
+	"context"
+	"regexp"
+
+	"mall-api/internal/pkg/errs"
+	"mall-api/internal/pkg/logger"
+)
+
+var (
+	errComponentInvalid = errs.Validation("组件名称只能包含小写字母、数字、下划线、中划线与点")
+	errDefaultLevelReq  = errs.Validation("全局默认级别不可移除，请指定级别")
+)
+
+// componentPattern 组件名称格式
+var componentPattern = regexp.MustCompile(`^[a-z0-9_.-]+$`)
+
+type service interface {
+	logLevels(ctx context.Context) *logLevelRes                             // 查询日志级别
+	setLogLevel(ctx context.Context, uid string, req *setLogLevelReq) error // 修改日志级别（仅当前实例，重启后恢复为配置值）
+}
+
+type svc struct{}
+
+func newService() service {
+	return &svc{}
+}
+
+func (s *svc) logLevels(context.Context) *logLevelRes {
+	st := logger.Levels()
+	res := &logLevelRes{Default: st.Default, Components: make([]componentLevelRes, 0, len(st.Components))}
+	for _, c := range st.Components {
+		res.Components = append(res.Components, componentLevelRes{Name: c.Name, Level: c.Level, Overridden: c.Overridden})
+	}
+	return res
+}
+
+func (s *svc) setLogLevel(ctx context.Context, uid string, req *setLogLevelReq) error {
+	component := req.Component
+	if component == "" {
+		component = logger.DefaultComponent
+	}
+	if !componentPattern.MatchString(component) {
+		return errComponentInvalid
+	}
+
+	// 1. 级别为空：移除组件的单独设置
+	if req.Level == "" {
+		if component == logger.DefaultComponent {
+			return errDefaultLevelReq
+		}
+		logger.ResetLevel(component)
+		log.WarnContext(ctx, "日志级别已恢复为全局默认", "target", component, "operator", uid)
+		return nil
+	}
+
+	// 2. 设置级别（请求参数已校验，此处的错误仅作兜底）
+	if err := logger.SetLevel(component, req.Level); err != nil {
+		return err
+	}
+	log.WarnContext(ctx, "日志级别已修改", "target", component, "level", req.Level, "operator", uid)
+	return nil
+}
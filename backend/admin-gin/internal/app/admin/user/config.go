@@ -0,0 +1,40 @@
+package user
+
+import (
+	"time"
+
+	"mall-api/internal/pkg/password"
+)
+
+// Config user 模块配置（由 boot 从 configs 转换后注入）
+type Config struct {
+	PasswordPolicy password.Policy // 密码策略：创建用户、管理员重置密码时校验
+
+	RecycleRetention time.Duration // 回收站保留期：删除超过该时长的用户被彻底删除，0 时取 defaultRecycleRetention，负数表示不清理
+	PurgeInterval    time.Duration // 回收站清理任务执行间隔，0 时取 defaultPurgeInterval
+}
+
+const (
+	defaultRecycleRetention = 30 * 24 * time.Hour
+	defaultPurgeInterval    = time.Hour
+	purgeBatchSize          = 100 // 单批彻底删除的用户数
+)
+
+// recycleRetention 回收站保留期，0 表示不清理
+func (c Config) recycleRetention() time.Duration {
+	switch {
+	case c.RecycleRetention < 0:
+		return 0
+	case c.RecycleRetention == 0:
+		return defaultRecycleRetention
+	default:
+		return c.RecycleRetention
+	}
+}
+
+func (c Config) purgeInterval() time.Duration {
+	if c.PurgeInterval > 0 {
+		return c.PurgeInterval
+	}
+	return defaultPurgeInterval
+}
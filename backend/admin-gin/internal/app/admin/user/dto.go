@@ -11,7 +11,7 @@ type listReq struct {
 	// 分页请求结构体复用
 	http.HttpPageRequest
 
-	// 角色：枚举
+	// 角色标识：筛选拥有该角色的用户
 	Role string `form:"role" binding:"omitempty"`
 
 	// 关键字：多字段综合搜索， email/username/uid
@@ -30,12 +30,15 @@ type listRes struct {
 	/** 邮箱 */
 	Email string `json:"email"`
 
-	/** 角色标识 */
-	Role string `json:"role"`
+	/** 角色标识列表 */
+	Roles []string `json:"roles"`
 
 	/** 账号状态 */
 	IsActive bool `json:"is_active"`
 
+	/** 下次登录须修改密码 */
+	MustChangePassword bool `json:"must_change_password"`
+
 	/** 创建时间 (JSON会自动格式化为 RFC3339 字符串) */
 	CreatedAt time.Time `json:"created_at"`
 
@@ -43,20 +46,56 @@ type listRes struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// 【重置密码】请求体
+type ResetPasswordReq struct {
+	// 选填，新密码（需满足密码策略）；不传时由服务端生成随机临时密码
+	Password string `json:"password"`
+}
+
+// 【重置密码】响应体
+type ResetPasswordRes struct {
+	/** 服务端生成的临时密码，仅返回一次（请求中指定了密码时为空） */
+	Password string `json:"password,omitempty"`
+}
+
+// 【回收站列表】查询参数
+type recycleReq struct {
+
+	// 分页请求结构体复用
+	http.HttpPageRequest
+
+	// 关键字：多字段综合搜索， email/username/uid
+	Keyword string `form:"keyword" binding:"omitempty"`
+}
+
+// 【回收站列表】响应体
+type recycleRes struct {
+	listRes
+
+	/** 删除时间 */
+	DeletedAt time.Time `json:"deleted_at"`
+
+	/** 预计彻底删除时间（回收站不清理时为空） */
+	PurgeAt *time.Time `json:"purge_at,omitempty"`
+}
+
 // 【新增】请求体
 type CreateReq struct {
 
 	// 必填，且通常有长度限制
 	Username string `json:"username" binding:"required,min=3,max=64"`
 
-	// 必填，创建时传入明文密码
-	Password string `json:"password" binding:"required,min=6,max=32"`
+	// 必填，创建时传入明文密码，由 service 层按密码策略统一校验
+	Password string `json:"password" binding:"required"`
 
 	// 选填，但如果有值必须符合邮箱格式
 	Email string `json:"email" binding:"omitempty,email"`
 
-	// 角色：不要写死 oneof，使用 user.Role(req.Role).IsValid() 统一校验（在 handler/service 层做）
-	Role string `json:"role" binding:"required"`
+	// 角色标识列表：角色可在运行时新增，不要写死 oneof，由 service 层通过 role 模块统一校验
+	Roles []string `json:"roles" binding:"required,min=1,dive,required"`
+
+	// 选填，下次登录须修改密码（管理员代设初始密码时建议开启）
+	MustChangePassword bool `json:"must_change_password"`
 }
 
 // 【新增】响应体
@@ -76,11 +115,14 @@ type UpdateReq struct {
 	// 允许修改邮箱
 	Email string `json:"email" binding:"omitempty,email"`
 
-	// 允许修改角色：不要写死 oneof，使用 user.Role(req.Role).IsValid() 统一校验（在 handler/service 层做）
-	Role string `json:"role" binding:"omitempty"`
+	// 允许修改角色（全量覆盖），不传或传空数组表示不修改；由 service 层通过 role 模块统一校验
+	Roles []string `json:"roles" binding:"omitempty,dive,required"`
 
 	// 使用指针，以便区分 "不修改" 和 "修改为禁用(false)"
 	IsActive *bool `json:"is_active"`
+
+	// 下次登录须修改密码：使用指针区分 "不修改" 和 "取消"
+	MustChangePassword *bool `json:"must_change_password"`
 }
 
 // 【修改】响应体
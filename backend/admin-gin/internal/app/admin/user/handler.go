@@ -1,14 +1,12 @@
 package user
 
 import (
-	"errors"
-	"net/http"
 	"strings"
 
+	"mall-api/internal/pkg/errs"
 	pkghttp "mall-api/internal/pkg/http"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
 type Handler struct {
@@ -19,15 +17,6 @@ func NewHandler(service Service) *Handler {
 	return &Handler{service: service}
 }
 
-// mapServiceErrorCode 将 service 层错误映射为统一的 HTTP 状态码
-// 约定：service 返回的 ValidationError => 422（参数/业务校验失败）
-func mapServiceErrorCode(err error) int {
-	if IsValidationError(err) {
-		return http.StatusUnprocessableEntity
-	}
-	return http.StatusInternalServerError
-}
-
 // @Summary		获取用户列表
 // @Description	支持分页以及条件查询
 // @ID				listUser
@@ -42,13 +31,13 @@ func mapServiceErrorCode(err error) int {
 func (h *Handler) List(c *gin.Context) {
 	var req listReq
 	if err := c.ShouldBindQuery(&req); err != nil {
-		pkghttp.Fail(c, http.StatusBadRequest, "参数错误")
+		c.Error(pkghttp.BindError(err))
 		return
 	}
 
 	res, total, err := h.service.List(c.Request.Context(), &req)
 	if err != nil {
-		pkghttp.Fail(c, mapServiceErrorCode(err), err.Error())
+		c.Error(err)
 		return
 	}
 
@@ -60,70 +49,189 @@ func (h *Handler) List(c *gin.Context) {
 	})
 }
 
-func (h *Handler) Create(c *gin.Context) {
-	var req CreateReq
-	if err := c.ShouldBindJSON(&req); err != nil {
-		pkghttp.Fail(c, http.StatusBadRequest, "参数错误")
+// @Summary		获取用户详情
+// @Description	按 UID 查询用户详情（含角色），已删除用户返回 404
+// @ID				getUser
+// @Security		BearerAuth
+// @Tags			User
+// @Accept			json
+// @Produce		json
+// @Param			uid	path		string							true	"用户 UID"
+// @Success		200	{object}	pkghttp.HttpResponse[listRes]	"查询成功"
+// @Router			/admin/user/{uid} [get]
+func (h *Handler) Get(c *gin.Context) {
+	res, err := h.service.Get(c.Request.Context(), c.Param("uid"))
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	// Role 校验（避免 dto 里写死 oneof 与常量不一致）
-	if !Role(req.Role).IsValid() {
-		pkghttp.Fail(c, http.StatusUnprocessableEntity, "role 不合法")
+	pkghttp.OK(c, res)
+}
+
+// @Summary		创建用户
+// @Description	创建后台用户并绑定角色；密码需满足密码策略，用户名 / 邮箱已存在返回 409，角色未定义返回 422
+// @Description	仅超级管理员可创建拥有 super_admin 角色的用户；授予的角色含操作人未拥有的权限点时返回 403
+// @ID				createUser
+// @Security		BearerAuth
+// @Tags			User
+// @Accept			json
+// @Produce		json
+// @Param			data	body		CreateReq						true	"用户信息"
+// @Success		200		{object}	pkghttp.HttpResponse[CreateRes]	"创建成功"
+// @Router			/admin/user [post]
+func (h *Handler) Create(c *gin.Context) {
+	var req CreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkghttp.BindError(err))
 		return
 	}
 
-	if err := h.service.Create(c.Request.Context(), &req); err != nil {
-		pkghttp.Fail(c, mapServiceErrorCode(err), err.Error())
+	if err := h.service.Create(c.Request.Context(), c.GetString("uid"), &req); err != nil {
+		c.Error(err)
 		return
 	}
 
 	pkghttp.OK(c, CreateRes{})
 }
 
+// @Summary		更新用户
+// @Description	部分更新邮箱、角色（全量覆盖）、启用状态、须修改密码标记；禁用后该用户已签发的令牌立即失效
+// @Description	不能禁用自己或修改自己的角色，不能禁用最后一个可用的超级管理员或移除其超级管理员角色（403）
+// @Description	仅超级管理员可修改超级管理员账号、授予或移除 super_admin 角色；新增的角色含操作人未拥有的权限点时返回 403
+// @ID				updateUser
+// @Security		BearerAuth
+// @Tags			User
+// @Accept			json
+// @Produce		json
+// @Param			uid		path		string							true	"用户 UID"
+// @Param			data	body		UpdateReq						true	"更新内容"
+// @Success		200		{object}	pkghttp.HttpResponse[UpdateRes]	"更新成功"
+// @Router			/admin/user/{uid} [put]
 func (h *Handler) Update(c *gin.Context) {
 	uid := strings.TrimSpace(c.Param("uid"))
 	if uid == "" {
-		pkghttp.Fail(c, http.StatusBadRequest, "uid 不能为空")
+		c.Error(errs.BadRequest("uid 不能为空"))
 		return
 	}
 
 	var req UpdateReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		pkghttp.Fail(c, http.StatusBadRequest, "参数错误")
+		c.Error(pkghttp.BindError(err))
 		return
 	}
 
-	if req.Role != "" && !Role(req.Role).IsValid() {
-		pkghttp.Fail(c, http.StatusUnprocessableEntity, "role 不合法")
+	if err := h.service.Update(c.Request.Context(), c.GetString("uid"), uid, &req); err != nil {
+		c.Error(err)
 		return
 	}
 
-	if err := h.service.Update(c.Request.Context(), uid, &req); err != nil {
-		pkghttp.Fail(c, mapServiceErrorCode(err), err.Error())
+	pkghttp.OK(c, UpdateRes{})
+}
+
+// @Summary		重置用户密码
+// @Description	管理员重置用户密码：指定的 password 需满足密码策略且不能与最近使用过的密码相同（422）；不传时生成随机临时密码并在响应中返回（仅返回一次）
+// @Description	重置后该用户下次登录须修改密码，已签发的令牌立即失效；不能重置自己的密码（403，请使用 /admin/auth/password）
+// @Description	仅超级管理员可重置超级管理员的密码（403）
+// @ID				resetUserPassword
+// @Security		BearerAuth
+// @Tags			User
+// @Accept			json
+// @Produce		json
+// @Param			uid		path		string									true	"用户 UID"
+// @Param			data	body		ResetPasswordReq						true	"新密码（可选）"
+// @Success		200		{object}	pkghttp.HttpResponse[ResetPasswordRes]	"重置成功"
+// @Router			/admin/user/{uid}/password [put]
+func (h *Handler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkghttp.BindError(err))
 		return
 	}
 
-	pkghttp.OK(c, UpdateRes{})
+	res, err := h.service.ResetPassword(c.Request.Context(), c.GetString("uid"), c.Param("uid"), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, res)
 }
 
+// @Summary		删除用户
+// @Description	软删除用户（移入回收站），该用户已签发的令牌立即失效；不能删除自己，不能删除最后一个可用的超级管理员（403）
+// @Description	仅超级管理员可删除超级管理员（403）
+// @Description	回收站中的用户可在保留期内恢复，超过保留期后被彻底删除；删除后用户名 / 邮箱可被新用户使用
+// @ID				deleteUser
+// @Security		BearerAuth
+// @Tags			User
+// @Accept			json
+// @Produce		json
+// @Param			uid	path		string							true	"用户 UID"
+// @Success		200	{object}	pkghttp.HttpResponse[DeleteRes]	"删除成功"
+// @Router			/admin/user/{uid} [delete]
 func (h *Handler) Delete(c *gin.Context) {
 	uid := strings.TrimSpace(c.Param("uid"))
 	if uid == "" {
-		pkghttp.Fail(c, http.StatusBadRequest, "uid 不能为空")
+		c.Error(errs.BadRequest("uid 不能为空"))
 		return
 	}
 
-	if err := h.service.Delete(c.Request.Context(), uid); err != nil {
-		// 如果你用 gorm.ErrRecordNotFound，可以映射成更友好的提示
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			pkghttp.Fail(c, http.StatusNotFound, "用户不存在")
-			return
-		}
-
-		pkghttp.Fail(c, mapServiceErrorCode(err), err.Error())
+	if err := h.service.Delete(c.Request.Context(), c.GetString("uid"), uid); err != nil {
+		c.Error(err)
 		return
 	}
 
 	pkghttp.OK(c, DeleteRes{})
 }
+
+// @Summary		回收站列表
+// @Description	分页查询已删除的用户，按删除时间倒序；purge_at 为预计彻底删除时间
+// @ID				listDeletedUser
+// @Security		BearerAuth
+// @Tags			User
+// @Accept			json
+// @Produce		json
+// @Param			params	query		recycleReq											true	"查询参数"
+// @Success		200		{object}	pkghttp.HttpResponse[pkghttp.PageRes[recycleRes]]	"查询成功"
+// @Router			/admin/user/recycle [get]
+func (h *Handler) ListDeleted(c *gin.Context) {
+	var req recycleReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(pkghttp.BindError(err))
+		return
+	}
+
+	res, total, err := h.service.ListDeleted(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OKWithPage(c, pkghttp.PageRes[recycleRes]{
+		List:  res,
+		Total: int64(total),
+		Page:  req.GetPage(),
+		Size:  req.GetPageSize(),
+	})
+}
+
+// @Summary		恢复用户
+// @Description	从回收站恢复用户（角色保持删除前的设置），用户需重新登录；用户名或邮箱已被其他用户使用时返回 409
+// @Description	仅超级管理员可恢复超级管理员（403）
+// @ID				restoreUser
+// @Security		BearerAuth
+// @Tags			User
+// @Accept			json
+// @Produce		json
+// @Param			uid	path		string						true	"用户 UID"
+// @Success		200	{object}	pkghttp.HttpResponse[Empty]	"恢复成功"
+// @Router			/admin/user/{uid}/restore [post]
+func (h *Handler) Restore(c *gin.Context) {
+	if err := h.service.Restore(c.Request.Context(), c.GetString("uid"), c.Param("uid")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pkghttp.OK(c, pkghttp.Empty{})
+}
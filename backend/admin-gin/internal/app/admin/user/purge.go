@@ -0,0 +1,43 @@
+package user
+
+import (
+	"context"
+	"time"
+)
+
+// PurgeJob 回收站清理任务：定期彻底删除超过保留期的已删除用户（由 boot 注册为后台任务，停机时取消）
+// 多实例部署时各实例都会执行，删除操作幂等
+type PurgeJob struct {
+	service Service
+	cfg     Config
+}
+
+func NewPurgeJob(service Service, cfg Config) *PurgeJob {
+	return &PurgeJob{service: service, cfg: cfg}
+}
+
+// Run 启动时执行一次，此后按间隔执行，直到 ctx 取消；保留期为负数时直接返回
+func (j *PurgeJob) Run(ctx context.Context) {
+	retention := j.cfg.recycleRetention()
+	if retention == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(j.cfg.purgeInterval())
+	defer ticker.Stop()
+
+	for {
+		n, err := j.service.Purge(ctx, time.Now().Add(-retention))
+		if err != nil {
+			log.ErrorContext(ctx, "回收站清理失败", "error", err.Error())
+		} else if n > 0 {
+			log.InfoContext(ctx, "回收站清理完成", "purged", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -1,14 +1,21 @@
 package user
 
 import (
+	"mall-api/internal/pkg/logger"
+
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
-func Register(rg *gin.RouterGroup, db *gorm.DB) {
-	repo := NewRepository(db)
-	svc := NewService(repo)
+// log 模块日志，级别由配置 log.levels.user 控制（未配置时跟随全局级别）
+var log = logger.Component("user")
+
+// Register 模块自组装并注册路由，返回回收站清理任务（由调用方决定何时启动）
+// 用户数据由 identity 模块统一管理，经 repo 注入
+func Register(rg *gin.RouterGroup, repo Repository, roles RoleBinder, guard AccountGuard, cfg Config) *PurgeJob {
+	svc := NewService(repo, roles, guard, cfg)
 	h := NewHandler(svc)
 
 	RegisterRouter(rg, h)
+
+	return NewPurgeJob(svc, cfg)
 }
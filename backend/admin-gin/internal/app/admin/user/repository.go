@@ -2,27 +2,46 @@ package user
 
 import (
 	"context"
-	"strings"
+	"time"
 
-	"gorm.io/gorm"
+	"mall-api/internal/app/admin/identity"
 )
 
+// Repository 用户管理所需的数据访问（由 identity.Store 实现，经 Register 注入）
 type Repository interface {
-	// List 分页查询用户列表（仅返回未删除数据），支持 role 精确筛选与 keyword 模糊匹配（uid/username/email）
-	List(ctx context.Context, page, size int, role, keyword string) ([]User, int, error)
+	// List 分页查询用户列表（仅返回未删除数据），支持按 uids 限定范围（nil 表示不限定）与 keyword 模糊匹配（uid/username/email）
+	List(ctx context.Context, page, size int, uids []string, keyword string) ([]identity.User, int, error)
+
+	// ListDeleted 分页查询已删除用户（回收站），按删除时间倒序，支持 keyword 模糊匹配（uid/username/email）
+	ListDeleted(ctx context.Context, page, size int, keyword string) ([]identity.User, int, error)
 
 	// Create 新增用户
-	Create(ctx context.Context, u *User) error
+	Create(ctx context.Context, u *identity.User) error
+
+	// GetByUID 按 UID 获取未删除的用户（用于更新前读取）
+	GetByUID(ctx context.Context, uid string) (*identity.User, error)
 
-	// GetByUID 按 UID 获取用户（用于更新前读取）
-	GetByUID(ctx context.Context, uid string) (*User, error)
+	// GetDeletedByUID 按 UID 获取已删除的用户（用于恢复前读取）
+	GetDeletedByUID(ctx context.Context, uid string) (*identity.User, error)
 
 	// UpdateByUID 按 UID 更新（部分字段更新）
 	UpdateByUID(ctx context.Context, uid string, updates map[string]any) error
 
-	// SoftDeleteByUID 软删除（is_deleted=true）
+	// SoftDeleteByUID 软删除（写入 deleted_at）
 	SoftDeleteByUID(ctx context.Context, uid string) error
 
+	// RestoreByUID 恢复已删除的用户（清空 deleted_at），返回是否恢复成功
+	RestoreByUID(ctx context.Context, uid string) (bool, error)
+
+	// DeletedBefore 查询删除时间早于 before 的用户 UID（最多 limit 个）
+	DeletedBefore(ctx context.Context, before time.Time, limit int) ([]string, error)
+
+	// PurgeByUIDs 彻底删除已软删除的用户（含历史密码），返回删除数量
+	PurgeByUIDs(ctx context.Context, uids []string) (int, error)
+
+	// CountActive 统计 uids 中已启用且未删除的用户数量
+	CountActive(ctx context.Context, uids []string) (int, error)
+
 	// ExistsByUsername 判断用户名是否存在（未删除）
 	ExistsByUsername(ctx context.Context, username string) (bool, error)
 
@@ -31,102 +50,10 @@ type Repository interface {
 
 	// ExistsByEmailExcludeUID 判断邮箱是否存在（排除某个 UID，用于更新时校验）
 	ExistsByEmailExcludeUID(ctx context.Context, email, excludeUID string) (bool, error)
-}
-
-type repo struct {
-	db *gorm.DB
-}
-
-func NewRepository(db *gorm.DB) Repository {
-	return &repo{db: db}
-}
-
-func (r *repo) baseQuery(ctx context.Context) *gorm.DB {
-	return r.db.WithContext(ctx).Model(&User{}).Where("is_deleted = ?", false)
-}
 
-func (r *repo) List(ctx context.Context, page, size int, role, keyword string) ([]User, int, error) {
-	q := r.baseQuery(ctx)
-
-	role = strings.TrimSpace(role)
-	if role != "" {
-		q = q.Where("role = ?", role)
-	}
-
-	keyword = strings.TrimSpace(keyword)
-	if keyword != "" {
-		like := "%" + keyword + "%"
-		q = q.Where("(uid ILIKE ? OR username ILIKE ? OR email ILIKE ?)", like, like, like)
-	}
-
-	var total int64
-	if err := q.Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
-
-	var list []User
-	if err := q.Order("created_at DESC").
-		Offset((page - 1) * size).
-		Limit(size).
-		Find(&list).Error; err != nil {
-		return nil, 0, err
-	}
-
-	return list, int(total), nil
-}
-
-func (r *repo) Create(ctx context.Context, u *User) error {
-	return r.db.WithContext(ctx).Create(u).Error
-}
-
-func (r *repo) GetByUID(ctx context.Context, uid string) (*User, error) {
-	var u User
-	if err := r.db.WithContext(ctx).Where("uid = ?", uid).First(&u).Error; err != nil {
-		return nil, err
-	}
-	return &u, nil
-}
-
-func (r *repo) UpdateByUID(ctx context.Context, uid string, updates map[string]any) error {
-	return r.db.WithContext(ctx).Model(&User{}).Where("uid = ?", uid).Updates(updates).Error
-}
-
-func (r *repo) SoftDeleteByUID(ctx context.Context, uid string) error {
-	return r.db.WithContext(ctx).Model(&User{}).Where("uid = ?", uid).Updates(map[string]any{
-		"is_deleted": true,
-	}).Error
-}
-
-func (r *repo) ExistsByUsername(ctx context.Context, username string) (bool, error) {
-	var count int64
-	if err := r.baseQuery(ctx).Where("username = ?", username).Count(&count).Error; err != nil {
-		return false, err
-	}
-	return count > 0, nil
-}
-
-func (r *repo) ExistsByEmail(ctx context.Context, email string) (bool, error) {
-	email = strings.TrimSpace(email)
-	if email == "" {
-		return false, nil
-	}
-
-	var count int64
-	if err := r.baseQuery(ctx).Where("email = ?", email).Count(&count).Error; err != nil {
-		return false, err
-	}
-	return count > 0, nil
-}
+	// PasswordHistory 查询最近 limit 个历史密码 hash（按记录时间倒序）
+	PasswordHistory(ctx context.Context, uid string, limit int) ([]string, error)
 
-func (r *repo) ExistsByEmailExcludeUID(ctx context.Context, email, excludeUID string) (bool, error) {
-	email = strings.TrimSpace(email)
-	if email == "" {
-		return false, nil
-	}
-
-	var count int64
-	if err := r.baseQuery(ctx).Where("email = ? AND uid <> ?", email, excludeUID).Count(&count).Error; err != nil {
-		return false, err
-	}
-	return count > 0, nil
+	// ResetPassword 更新密码并设置强制修改标记，旧密码写入历史，只保留最近 keep 个
+	ResetPassword(ctx context.Context, uid, hash, oldHash string, keep int) error
 }
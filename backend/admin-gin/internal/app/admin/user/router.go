@@ -1,6 +1,7 @@
 package user
 
 import (
+	"mall-api/internal/app/admin/iam/role"
 	"mall-api/internal/pkg/middleware"
 
 	"github.com/gin-gonic/gin"
@@ -10,9 +11,13 @@ func RegisterRouter(r *gin.RouterGroup, handlers *Handler) {
 	ug := r.Group("/user")
 	ug.Use(middleware.JWT())
 	{
-		ug.GET("", handlers.List)
-		// ug.POST("", handlers.Create)
-		// ug.PUT("/:id", handlers.Update)
-		// ug.DELETE("/:id", handlers.Delete)
+		ug.GET("", middleware.RequirePermission(role.PermUserList), handlers.List)
+		ug.GET("/recycle", middleware.RequirePermission(role.PermUserDelete), handlers.ListDeleted) // 回收站
+		ug.GET("/:uid", middleware.RequirePermission(role.PermUserList), handlers.Get)
+		ug.POST("", middleware.RequirePermission(role.PermUserCreate), handlers.Create)
+		ug.PUT("/:uid", middleware.RequirePermission(role.PermUserUpdate), handlers.Update)
+		ug.PUT("/:uid/password", middleware.RequirePermission(role.PermUserUpdate), handlers.ResetPassword) // 管理员重置密码
+		ug.DELETE("/:uid", middleware.RequirePermission(role.PermUserDelete), handlers.Delete)
+		ug.POST("/:uid/restore", middleware.RequirePermission(role.PermUserDelete), handlers.Restore) // 从回收站恢复
 	}
 }
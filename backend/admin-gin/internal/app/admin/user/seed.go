@@ -0,0 +1,60 @@
+package user
+
+import (
+	"context"
+	"strings"
+
+	"mall-api/internal/app/admin/iam/role"
+	"mall-api/internal/pkg/password"
+)
+
+// SuperAdmin 首个超级管理员参数（cmd/seed 使用）
+type SuperAdmin struct {
+	Username string
+	Email    string
+	Password string // 为空时按密码策略生成临时密码，首次登录须修改
+}
+
+// SeedResult 初始化超级管理员的结果
+type SeedResult struct {
+	Created  bool   // false 表示已存在可用的超级管理员，未做任何修改
+	Password string // 生成的临时密码（仅本次返回，传入密码时为空）
+}
+
+// SeedSuperAdmin 初始化首个超级管理员（幂等）：已存在可用的超级管理员时直接返回
+// 用户名已被普通用户占用时返回校验错误，不会把已有账号提升为超级管理员
+func SeedSuperAdmin(ctx context.Context, repo Repository, roles RoleBinder, policy password.Policy, admin SuperAdmin) (*SeedResult, error) {
+	s := &service{repo: repo, roles: roles, cfg: Config{PasswordPolicy: policy}}
+
+	uids, err := roles.UsersWithRole(ctx, role.CodeSuperAdmin)
+	if err != nil {
+		return nil, err
+	}
+	n, err := repo.CountActive(ctx, uids)
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		return &SeedResult{}, nil
+	}
+
+	res := &SeedResult{Created: true}
+	admin.Username = strings.TrimSpace(admin.Username)
+	if admin.Password == "" {
+		if res.Password, err = policy.Generate(admin.Username); err != nil {
+			return nil, err
+		}
+		admin.Password = res.Password
+	}
+
+	if err := s.create(ctx, &CreateReq{
+		Username:           admin.Username,
+		Password:           admin.Password,
+		Email:              admin.Email,
+		Roles:              []string{role.CodeSuperAdmin},
+		MustChangePassword: res.Password != "",
+	}); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
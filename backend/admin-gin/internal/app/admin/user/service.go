@@ -3,76 +3,173 @@ package user
 import (
 	"context"
 	"errors"
+	"slices"
 	"strings"
 	"time"
 
+	"mall-api/internal/app/admin/iam/role"
+	"mall-api/internal/app/admin/identity"
+	"mall-api/internal/pkg/errs"
 	"mall-api/internal/pkg/uuid"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
-// ValidationError 用于标记“参数/业务校验类错误”，方便 handler 层统一映射为 422。
-type ValidationError struct {
-	msg string
-}
+var (
+	ErrUserNotFound = errs.NotFound("用户不存在")
+
+	ErrRestoreConflict = errs.Conflict("用户名或邮箱已被其他用户使用，无法恢复")
+	errUsernameExists  = errs.Conflict("用户名已存在")
+	errEmailExists     = errs.Conflict("邮箱已存在")
 
-func (e *ValidationError) Error() string { return e.msg }
+	// 以下为防止管理员误操作导致无人可管理系统的保护（403）
+	ErrDeleteSelf     = errs.Forbidden("不能删除自己的账号")
+	ErrDemoteSelf     = errs.Forbidden("不能禁用自己的账号或修改自己的角色")
+	ErrResetSelf      = errs.Forbidden("不能重置自己的密码，请通过修改密码接口操作")
+	ErrLastSuperAdmin = errs.Forbidden("至少需要保留一个可用的超级管理员")
+
+	// 提权保护：超级管理员账号及超级管理员角色的授予 / 移除仅超级管理员可操作（403）
+	ErrSuperAdminRequired = errs.Forbidden("仅超级管理员可管理超级管理员账号或授予、移除超级管理员角色")
+	ErrRoleGrantExceeds   = errs.Forbidden("不能授予包含自己未拥有的权限点的角色")
+)
 
-func newValidationError(msg string) error { return &ValidationError{msg: msg} }
+// RoleBinder 用户角色绑定能力（由 iam/role 模块实现，经 boot 注入），user 模块不直接依赖 role 的实现
+type RoleBinder interface {
+	// RolesOfUsers 批量查询用户的角色标识
+	RolesOfUsers(ctx context.Context, uids []string) (map[string][]string, error)
+	// UsersWithRole 查询拥有某角色的用户 UID
+	UsersWithRole(ctx context.Context, code string) ([]string, error)
+	// Exist 判断角色标识是否全部存在
+	Exist(ctx context.Context, codes []string) (bool, error)
+	// Assign 全量覆盖用户的角色
+	Assign(ctx context.Context, uid string, codes []string) error
+	// UnassignUsers 删除用户的全部角色关联（彻底删除用户时）
+	UnassignUsers(ctx context.Context, uids []string) error
+	// PermissionsOf 查询用户通过已启用角色获得的权限点标识
+	PermissionsOf(ctx context.Context, uid string) ([]string, error)
+	// PermissionsOfRoles 查询角色绑定的权限点标识
+	PermissionsOfRoles(ctx context.Context, codes []string) ([]string, error)
+}
 
-func IsValidationError(err error) bool {
-	var ve *ValidationError
-	return errors.As(err, &ve)
+// AccountGuard 账号认证状态维护能力（由 iam/auth 模块实现，经 boot 注入），用户被禁用或删除后立即生效
+type AccountGuard interface {
+	// RevokeUser 吊销用户已签发的全部 token 并下线其全部会话
+	RevokeUser(ctx context.Context, uid string) error
+	// InvalidateStatus 失效账号状态缓存
+	InvalidateStatus(ctx context.Context, uid string) error
+	// PurgeUsers 清理用户的认证数据（彻底删除用户时）
+	PurgeUsers(ctx context.Context, uids []string) error
 }
 
 type Service interface {
 	// List 分页查询后台用户列表
 	List(ctx context.Context, req *listReq) ([]listRes, int, error)
-	// Create 创建后台用户
-	Create(ctx context.Context, req *CreateReq) error
-	// Update 按 UID 更新后台用户（邮箱/角色/启用状态）
-	Update(ctx context.Context, uid string, req *UpdateReq) error
-	// Delete 按 UID 删除后台用户（软删除）
-	Delete(ctx context.Context, uid string) error
+	// Get 按 UID 查询后台用户详情
+	Get(ctx context.Context, uid string) (*listRes, error)
+	// Create 创建后台用户，operator 为操作人 UID
+	Create(ctx context.Context, operator string, req *CreateReq) error
+	// Update 按 UID 更新后台用户（邮箱/角色/启用状态），operator 为操作人 UID
+	Update(ctx context.Context, operator, uid string, req *UpdateReq) error
+	// Delete 按 UID 删除后台用户（软删除），operator 为操作人 UID
+	Delete(ctx context.Context, operator, uid string) error
+	// ResetPassword 管理员重置用户密码，重置后用户须在下次登录时修改密码
+	ResetPassword(ctx context.Context, operator, uid string, req *ResetPasswordReq) (*ResetPasswordRes, error)
+	// ListDeleted 分页查询回收站中的用户
+	ListDeleted(ctx context.Context, req *recycleReq) ([]recycleRes, int, error)
+	// Restore 从回收站恢复用户（用户名 / 邮箱已被占用时返回 ErrRestoreConflict），operator 为操作人 UID
+	Restore(ctx context.Context, operator, uid string) error
+	// Purge 彻底删除删除时间早于 before 的用户及其关联数据，返回删除数量
+	Purge(ctx context.Context, before time.Time) (int, error)
 }
 
 type service struct {
-	repo Repository
+	repo  Repository
+	roles RoleBinder
+	guard AccountGuard
+	cfg   Config
 }
 
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+func NewService(repo Repository, roles RoleBinder, guard AccountGuard, cfg Config) Service {
+	return &service{repo: repo, roles: roles, guard: guard, cfg: cfg}
 }
 
 func (s *service) List(ctx context.Context, req *listReq) ([]listRes, int, error) {
 	page := req.GetPage()
 	size := req.GetPageSize()
 
-	users, total, err := s.repo.List(ctx, page, size, strings.TrimSpace(req.Role), strings.TrimSpace(req.Keyword))
+	// 按角色筛选：先由 role 模块查出拥有该角色的用户，再限定查询范围
+	var uids []string
+	if role := strings.TrimSpace(req.Role); role != "" {
+		var err error
+		if uids, err = s.roles.UsersWithRole(ctx, role); err != nil {
+			return nil, 0, err
+		}
+		if len(uids) == 0 {
+			return []listRes{}, 0, nil
+		}
+	}
+
+	users, total, err := s.repo.List(ctx, page, size, uids, strings.TrimSpace(req.Keyword))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 批量查询角色，避免 N+1
+	listUIDs := make([]string, 0, len(users))
+	for _, u := range users {
+		listUIDs = append(listUIDs, u.UID)
+	}
+	rolesOf, err := s.roles.RolesOfUsers(ctx, listUIDs)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	out := make([]listRes, 0, len(users))
 	for _, u := range users {
-		out = append(out, listRes{
-			ID:        u.UID,
-			Username:  u.Username,
-			Email:     u.Email,
-			Role:      u.Role,
-			IsActive:  u.IsActive,
-			CreatedAt: u.CreatedAt,
-			UpdatedAt: u.UpdatedAt,
-		})
+		out = append(out, toListRes(&u, rolesOf[u.UID]))
 	}
 	return out, total, nil
 }
 
-func (s *service) Create(ctx context.Context, req *CreateReq) error {
-	// 角色校验（保持与 constant.go 一致）
-	if !Role(req.Role).IsValid() {
-		return newValidationError("role 不合法")
+func (s *service) Get(ctx context.Context, uid string) (*listRes, error) {
+	u, err := s.getUser(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	rolesOf, err := s.roles.RolesOfUsers(ctx, []string{u.UID})
+	if err != nil {
+		return nil, err
+	}
+
+	res := toListRes(u, rolesOf[u.UID])
+	return &res, nil
+}
+
+func (s *service) Create(ctx context.Context, operator string, req *CreateReq) error {
+	// 提权保护：仅超级管理员可创建超级管理员；授予的角色不能超出操作人自己的权限
+	if slices.Contains(trimCodes(req.Roles), role.CodeSuperAdmin) {
+		if err := s.requireSuperAdmin(ctx, operator); err != nil {
+			return err
+		}
+	}
+	if err := s.checkGrant(ctx, operator, nil, req.Roles); err != nil {
+		return err
+	}
+	return s.create(ctx, req)
+}
+
+// create 创建用户并绑定角色（不做操作人校验，cmd/seed 初始化超级管理员时直接使用）
+func (s *service) create(ctx context.Context, req *CreateReq) error {
+	// 角色校验（角色由 role 模块在运行时维护）
+	if err := s.checkRoles(ctx, req.Roles); err != nil {
+		return err
+	}
+
+	// 密码策略校验
+	if err := s.cfg.PasswordPolicy.Validate(req.Password, req.Username); err != nil {
+		return err
 	}
 
 	// 用户名唯一性检查
@@ -81,7 +178,7 @@ func (s *service) Create(ctx context.Context, req *CreateReq) error {
 		return err
 	}
 	if exist {
-		return newValidationError("用户名已存在")
+		return errUsernameExists
 	}
 
 	// 邮箱唯一性检查（如果传了 email）
@@ -91,7 +188,7 @@ func (s *service) Create(ctx context.Context, req *CreateReq) error {
 			return err
 		}
 		if existEmail {
-			return newValidationError("邮箱已存在")
+			return errEmailExists
 		}
 	}
 
@@ -103,75 +200,450 @@ func (s *service) Create(ctx context.Context, req *CreateReq) error {
 	}
 
 	now := time.Now()
-	u := &User{
-		UID:       uid,
-		Username:  req.Username,
-		Email:     strings.TrimSpace(req.Email),
-		Password:  string(hashedPassword),
-		Role:      req.Role,
-		IsActive:  true,
-		IsDeleted: false,
-		CreatedAt: now,
-		UpdatedAt: now,
+	u := &identity.User{
+		UID:                uid,
+		Username:           req.Username,
+		Email:              identity.NormalizeEmail(req.Email),
+		Password:           string(hashedPassword),
+		IsActive:           true,
+		MustChangePassword: req.MustChangePassword,
+		CreatedAt:          now,
+		UpdatedAt:          now,
 	}
 
-	return s.repo.Create(ctx, u)
+	if err := s.repo.Create(ctx, u); err != nil {
+		return err
+	}
+	return s.roles.Assign(ctx, uid, req.Roles)
 }
 
-func (s *service) Update(ctx context.Context, uid string, req *UpdateReq) error {
-	uid = strings.TrimSpace(uid)
-	if uid == "" {
-		return newValidationError("uid 不能为空")
+func (s *service) Update(ctx context.Context, operator, uid string, req *UpdateReq) error {
+	// 查询目标用户
+	u, err := s.getUser(ctx, uid)
+	if err != nil {
+		return err
 	}
+	uid = u.UID
 
-	// 查询目标用户
-	u, err := s.repo.GetByUID(ctx, uid)
+	current, err := s.rolesOf(ctx, uid)
 	if err != nil {
 		return err
 	}
-	if u.IsDeleted {
-		return gorm.ErrRecordNotFound
+
+	// 降权保护：不能禁用自己 / 修改自己的角色；不能禁用最后一个超级管理员或移除其超级管理员角色
+	disable := req.IsActive != nil && !*req.IsActive && u.IsActive
+	rolesChanged, addSuperAdmin, dropSuperAdmin := diffRoles(current, req.Roles)
+	if uid == operator && (disable || rolesChanged) {
+		return ErrDemoteSelf
+	}
+
+	// 提权保护：修改超级管理员账号（邮箱可用于找回密码）、授予超级管理员角色须由超级管理员操作
+	if slices.Contains(current, role.CodeSuperAdmin) || addSuperAdmin {
+		if err := s.requireSuperAdmin(ctx, operator); err != nil {
+			return err
+		}
+	}
+	// 新增的角色不能超出操作人自己的权限
+	if err := s.checkGrant(ctx, operator, current, req.Roles); err != nil {
+		return err
+	}
+	if disable || dropSuperAdmin {
+		if err := s.checkLastSuperAdmin(ctx, u); err != nil {
+			return err
+		}
 	}
 
 	// 更新字段（部分更新）
 	updates := map[string]any{}
 
 	if req.Email != "" {
-		email := strings.TrimSpace(req.Email)
+		email := identity.NormalizeEmail(req.Email)
 		// 邮箱唯一性检查
 		existEmail, err := s.repo.ExistsByEmailExcludeUID(ctx, email, uid)
 		if err != nil {
 			return err
 		}
 		if existEmail {
-			return newValidationError("邮箱已存在")
+			return errEmailExists
 		}
 		updates["email"] = email
 	}
 
-	if req.Role != "" {
-		if !Role(req.Role).IsValid() {
-			return newValidationError("role 不合法")
+	if len(req.Roles) > 0 {
+		if err := s.checkRoles(ctx, req.Roles); err != nil {
+			return err
 		}
-		updates["role"] = req.Role
 	}
 
 	if req.IsActive != nil {
 		updates["is_active"] = *req.IsActive
 	}
 
-	if len(updates) == 0 {
-		return nil
+	if req.MustChangePassword != nil {
+		updates["must_change_password"] = *req.MustChangePassword
 	}
 
-	updates["updated_at"] = time.Now()
-	return s.repo.UpdateByUID(ctx, uid, updates)
+	if len(updates) > 0 {
+		updates["updated_at"] = time.Now()
+		if err := s.repo.UpdateByUID(ctx, uid, updates); err != nil {
+			return err
+		}
+	}
+
+	// 启用状态 / 强制修改密码变更：失效状态缓存，下一次请求即生效；禁用时已签发的令牌立即失效
+	activeChanged := req.IsActive != nil && *req.IsActive != u.IsActive
+	if activeChanged || (req.MustChangePassword != nil && *req.MustChangePassword != u.MustChangePassword) {
+		if err := s.guard.InvalidateStatus(ctx, uid); err != nil {
+			return err
+		}
+	}
+	if activeChanged && !*req.IsActive {
+		if err := s.guard.RevokeUser(ctx, uid); err != nil {
+			return err
+		}
+	}
+
+	if len(req.Roles) > 0 {
+		return s.roles.Assign(ctx, uid, req.Roles)
+	}
+	return nil
+}
+
+func (s *service) Delete(ctx context.Context, operator, uid string) error {
+	u, err := s.getUser(ctx, uid)
+	if err != nil {
+		return err
+	}
+	uid = u.UID
+
+	// 删除保护：不能删除自己，不能删除最后一个超级管理员；超级管理员仅可由超级管理员删除
+	if uid == operator {
+		return ErrDeleteSelf
+	}
+	if err := s.guardSuperAdmin(ctx, operator, uid); err != nil {
+		return err
+	}
+	if err := s.checkLastSuperAdmin(ctx, u); err != nil {
+		return err
+	}
+
+	if err := s.repo.SoftDeleteByUID(ctx, uid); err != nil {
+		return err
+	}
+
+	// 已删除用户：失效状态缓存，已签发的令牌立即失效
+	if err := s.guard.InvalidateStatus(ctx, uid); err != nil {
+		return err
+	}
+	return s.guard.RevokeUser(ctx, uid)
+}
+
+func (s *service) ResetPassword(ctx context.Context, operator, uid string, req *ResetPasswordReq) (*ResetPasswordRes, error) {
+	u, err := s.getUser(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	uid = u.UID
+
+	// 重置自己的密码会绕过原密码校验，须走修改密码接口
+	if uid == operator {
+		return nil, ErrResetSelf
+	}
+	// 提权保护：重置超级管理员的密码须由超级管理员操作（临时密码会在响应中返回）
+	if err := s.guardSuperAdmin(ctx, operator, uid); err != nil {
+		return nil, err
+	}
+
+	// 指定密码时按策略校验且不可复用最近 N 次使用过的密码（与用户自行修改密码一致），否则生成随机临时密码
+	policy := s.cfg.PasswordPolicy
+	keep := max(policy.HistorySize()-1, 0)
+	res := &ResetPasswordRes{}
+	pw := req.Password
+	if pw != "" {
+		if err := policy.Validate(pw, u.Username); err != nil {
+			return nil, err
+		}
+		if policy.HistorySize() > 0 {
+			history, err := s.repo.PasswordHistory(ctx, uid, keep)
+			if err != nil {
+				return nil, err
+			}
+			if err := policy.Reused(pw, append([]string{u.Password}, history...)); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		if pw, err = policy.Generate(u.Username); err != nil {
+			return nil, err
+		}
+		res.Password = pw
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(pw), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	// 旧密码转入历史
+	if err := s.repo.ResetPassword(ctx, uid, string(hashedPassword), u.Password, keep); err != nil {
+		return nil, err
+	}
+
+	// 须修改密码：失效状态缓存；旧密码可能已泄露，已签发的令牌立即失效
+	if err := s.guard.InvalidateStatus(ctx, uid); err != nil {
+		return nil, err
+	}
+	if err := s.guard.RevokeUser(ctx, uid); err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
-func (s *service) Delete(ctx context.Context, uid string) error {
+func (s *service) ListDeleted(ctx context.Context, req *recycleReq) ([]recycleRes, int, error) {
+	users, total, err := s.repo.ListDeleted(ctx, req.GetPage(), req.GetPageSize(), strings.TrimSpace(req.Keyword))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	uids := make([]string, 0, len(users))
+	for _, u := range users {
+		uids = append(uids, u.UID)
+	}
+	rolesOf, err := s.roles.RolesOfUsers(ctx, uids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	retention := s.cfg.recycleRetention()
+	out := make([]recycleRes, 0, len(users))
+	for _, u := range users {
+		item := recycleRes{listRes: toListRes(&u, rolesOf[u.UID]), DeletedAt: u.DeletedAt.Time}
+		if retention > 0 {
+			purgeAt := u.DeletedAt.Time.Add(retention)
+			item.PurgeAt = &purgeAt
+		}
+		out = append(out, item)
+	}
+	return out, total, nil
+}
+
+func (s *service) Restore(ctx context.Context, operator, uid string) error {
+	uid = strings.TrimSpace(uid)
+	if uid == "" {
+		return errs.Validation("uid 不能为空")
+	}
+
+	u, err := s.repo.GetDeletedByUID(ctx, uid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	// 提权保护：超级管理员仅可由超级管理员恢复（删除时保留了角色）
+	if err := s.guardSuperAdmin(ctx, operator, uid); err != nil {
+		return err
+	}
+
+	// 删除期间用户名 / 邮箱可能已被新用户使用
+	exist, err := s.repo.ExistsByUsername(ctx, u.Username)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		exist, err = s.repo.ExistsByEmail(ctx, u.Email)
+		if err != nil {
+			return err
+		}
+	}
+	if exist {
+		return ErrRestoreConflict
+	}
+
+	ok, err := s.repo.RestoreByUID(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrUserNotFound
+	}
+
+	// 删除时写入的 "不可用" 状态缓存立即失效；删除时已下线的会话不会恢复，用户需重新登录
+	return s.guard.InvalidateStatus(ctx, uid)
+}
+
+func (s *service) Purge(ctx context.Context, before time.Time) (int, error) {
+	total := 0
+	for {
+		uids, err := s.repo.DeletedBefore(ctx, before, purgeBatchSize)
+		if err != nil || len(uids) == 0 {
+			return total, err
+		}
+
+		// 先清理其他模块中的关联数据，再删除用户：中途失败时用户仍在回收站中，下一轮可继续清理
+		if err := s.roles.UnassignUsers(ctx, uids); err != nil {
+			return total, err
+		}
+		if err := s.guard.PurgeUsers(ctx, uids); err != nil {
+			return total, err
+		}
+		n, err := s.repo.PurgeByUIDs(ctx, uids)
+		total += n
+		if err != nil || len(uids) < purgeBatchSize {
+			return total, err
+		}
+	}
+}
+
+// toListRes 转换为列表 / 详情响应
+func toListRes(u *identity.User, roles []string) listRes {
+	return listRes{
+		ID:                 u.UID,
+		Username:           u.Username,
+		Email:              u.Email,
+		Roles:              roles,
+		IsActive:           u.IsActive,
+		MustChangePassword: u.MustChangePassword,
+		CreatedAt:          u.CreatedAt,
+		UpdatedAt:          u.UpdatedAt,
+	}
+}
+
+// getUser 按 UID 查询未删除的用户，不存在时返回 ErrUserNotFound
+func (s *service) getUser(ctx context.Context, uid string) (*identity.User, error) {
 	uid = strings.TrimSpace(uid)
 	if uid == "" {
-		return newValidationError("uid 不能为空")
+		return nil, errs.Validation("uid 不能为空")
+	}
+
+	u, err := s.repo.GetByUID(ctx, uid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// rolesOf 查询用户当前的角色标识
+func (s *service) rolesOf(ctx context.Context, uid string) ([]string, error) {
+	rolesOf, err := s.roles.RolesOfUsers(ctx, []string{uid})
+	if err != nil {
+		return nil, err
+	}
+	return rolesOf[uid], nil
+}
+
+// requireSuperAdmin 操作人须拥有超级管理员角色，否则返回 ErrSuperAdminRequired
+func (s *service) requireSuperAdmin(ctx context.Context, operator string) error {
+	roles, err := s.rolesOf(ctx, operator)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(roles, role.CodeSuperAdmin) {
+		return ErrSuperAdminRequired
+	}
+	return nil
+}
+
+// guardSuperAdmin 目标用户为超级管理员时，操作人须同为超级管理员
+func (s *service) guardSuperAdmin(ctx context.Context, operator, uid string) error {
+	roles, err := s.rolesOf(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(roles, role.CodeSuperAdmin) {
+		return nil
+	}
+	return s.requireSuperAdmin(ctx, operator)
+}
+
+// checkGrant 新增授予的角色（codes 中不在 current 内的角色）所含权限点须均为操作人已拥有的权限点
+// 避免持有 user:create / user:update 的操作人借助角色分配获得自己没有的权限（拥有通配权限点 * 时不限制）
+func (s *service) checkGrant(ctx context.Context, operator string, current, codes []string) error {
+	granted := slices.DeleteFunc(trimCodes(codes), func(c string) bool { return slices.Contains(current, c) })
+	if len(granted) == 0 {
+		return nil
+	}
+
+	own, err := s.roles.PermissionsOf(ctx, operator)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(own, role.PermissionAll) {
+		return nil
+	}
+	perms, err := s.roles.PermissionsOfRoles(ctx, granted)
+	if err != nil {
+		return err
+	}
+	for _, p := range perms {
+		if !slices.Contains(own, p) {
+			return ErrRoleGrantExceeds
+		}
+	}
+	return nil
+}
+
+// diffRoles 比较更新后的角色与当前角色：是否有变化、是否授予 / 移除了超级管理员角色（codes 为空表示不修改）
+func diffRoles(current, codes []string) (changed, addSuperAdmin, dropSuperAdmin bool) {
+	if len(codes) == 0 {
+		return false, false, false
+	}
+
+	current = slices.Clone(current)
+	next := trimCodes(codes)
+	slices.Sort(current)
+	slices.Sort(next)
+
+	had, has := slices.Contains(current, role.CodeSuperAdmin), slices.Contains(next, role.CodeSuperAdmin)
+	changed = !slices.Equal(slices.Compact(current), slices.Compact(next))
+	return changed, !had && has, had && !has
+}
+
+// trimCodes 去除角色标识首尾空白（返回副本）
+func trimCodes(codes []string) []string {
+	out := make([]string, len(codes))
+	for i, c := range codes {
+		out[i] = strings.TrimSpace(c)
+	}
+	return out
+}
+
+// checkLastSuperAdmin 目标用户为可用的超级管理员时，确认仍有其他可用的超级管理员
+func (s *service) checkLastSuperAdmin(ctx context.Context, u *identity.User) error {
+	if !u.IsActive {
+		return nil
+	}
+
+	uids, err := s.roles.UsersWithRole(ctx, role.CodeSuperAdmin)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(uids, u.UID) {
+		return nil
+	}
+
+	others := slices.DeleteFunc(uids, func(v string) bool { return v == u.UID })
+	n, err := s.repo.CountActive(ctx, others)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLastSuperAdmin
+	}
+	return nil
+}
+
+// checkRoles 校验角色标识是否均已在 role 模块中定义
+func (s *service) checkRoles(ctx context.Context, codes []string) error {
+	ok, err := s.roles.Exist(ctx, codes)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errs.Validation("role 不合法")
 	}
-	return s.repo.SoftDeleteByUID(ctx, uid)
+	return nil
 }
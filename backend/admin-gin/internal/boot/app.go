@@ -2,15 +2,26 @@
 package boot
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"mall-api/configs"
 	"mall-api/internal/pkg/cookie"
 	"mall-api/internal/pkg/database"
+	"mall-api/internal/pkg/health"
 	"mall-api/internal/pkg/jwt"
+	"mall-api/internal/pkg/lifecycle"
 	"mall-api/internal/pkg/logger"
+	"mall-api/internal/pkg/mail"
+	"mall-api/internal/pkg/metrics"
 	"mall-api/internal/pkg/middleware"
+	"mall-api/internal/pkg/tracing"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -26,22 +37,58 @@ type App struct {
 	Ge  *gin.Engine
 	Se  *http.Server
 	Cm  *cookie.CookieManager
+	Ml  mail.Mailer          // 邮件发送：mail.Queue 异步发送，停机时发送完队列中剩余的邮件
+	Lc  *lifecycle.Lifecycle // 生命周期：模块通过 Lc.Go 注册后台任务，通过 Lc.Append 注册需要在停机时释放的资源
+	Hc  *health.Health       // 存活与就绪探针：模块通过 Hc.Register 注册需要纳入 /readyz 的依赖检查
+
+	shutdownTimeout time.Duration // 优雅停机总时长（等待进行中的请求、后台任务退出）
+	shutdownDelay   time.Duration // 置为未就绪后、停止 HTTP 服务前的等待时间
+	serveErr        chan error    // HTTP 服务异常退出
 }
 
+// defaultShutdownTimeout 优雅停机默认等待时间
+const defaultShutdownTimeout = 15 * time.Second
+
 func NewApp(cfg *configs.Config) (*App, error) {
 
 	// 1. 构造 log
-	log := logger.NewLog(logger.Config{
+	log, logErr := logger.NewLog(logger.Config{
 		Level:    cfg.Log.Level,
+		Levels:   cfg.Log.Levels,
 		Format:   cfg.Log.Format,
 		Dir:      cfg.Log.Dir,
 		Filename: cfg.Log.Filename,
 		MaxSize:  cfg.Log.MaxSize,
 		MaxAge:   cfg.Log.MaxAge,
 		Compress: cfg.Log.Compress,
+		Redact: logger.RedactConfig{
+			Fields:   cfg.Log.Redact.Fields,
+			Headers:  cfg.Log.Redact.Headers,
+			Patterns: cfg.Log.Redact.Patterns,
+		},
 	})
+	if logErr != nil {
+		return nil, logErr
+	}
 	slog.SetDefault(log) // 设置全局默认slog，这里设置了，全局调用slog的地方都会用这个log
 
+	// 生命周期：先注册的后停止；链路追踪最先注册，停机时最后导出剩余的 span
+	lc := lifecycle.New()
+	shutdownTracing, traceErr := tracing.New(context.Background(), tracing.Config{
+		ServiceName:    cfg.App.Name,
+		ServiceVersion: cfg.App.Version,
+		Exporter:       cfg.Tracing.Exporter,
+		Endpoint:       cfg.Tracing.Endpoint,
+		SampleRatio:    cfg.Tracing.SampleRatio,
+	})
+	if traceErr != nil {
+		return nil, traceErr
+	}
+	lc.Append(lifecycle.Hook{
+		Name:   "tracing",
+		OnStop: shutdownTracing,
+	})
+
 	// 2. 构造 gorm
 	db, dbErr := database.NewPostgre(&database.PostgreConfig{
 		Host:            cfg.Database.Host,
@@ -53,10 +100,32 @@ func NewApp(cfg *configs.Config) (*App, error) {
 		MaxIdleConns:    cfg.Database.MaxIdleConns,
 		MaxOpenConns:    cfg.Database.MaxOpenConns,
 		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+		Logger:          logger.NewGormLogger(time.Duration(cfg.Log.SlowThreshold) * time.Millisecond), // SQL 日志经组件 logger "gorm" 输出
 	})
 	if dbErr != nil {
 		return nil, dbErr
 	}
+	sqlDB, dbErr := db.DB()
+	if dbErr != nil {
+		return nil, dbErr
+	}
+	if cfg.Metrics.Enabled {
+		if err := metrics.InstrumentDB(db, cfg.Database.DBName); err != nil {
+			return nil, err
+		}
+	}
+	hc := health.New(time.Duration(cfg.Server.HealthCheckTimeout) * time.Second)
+	hc.Register("postgres", 0, health.SQL(sqlDB))
+
+	if err := tracing.InstrumentDB(db); err != nil {
+		return nil, err
+	}
+
+	// 数据库与 redis 在 HTTP 服务、后台任务停止之后关闭
+	lc.Append(lifecycle.Hook{
+		Name:   "postgres",
+		OnStop: func(context.Context) error { return sqlDB.Close() },
+	})
 
 	// 3. 构造 redis
 	rdb, rdbErr := database.NewRedis(&database.RedisConfig{
@@ -70,26 +139,52 @@ func NewApp(cfg *configs.Config) (*App, error) {
 	if rdbErr != nil {
 		return nil, rdbErr
 	}
+	if cfg.Metrics.Enabled {
+		if err := metrics.InstrumentRedis(rdb); err != nil {
+			return nil, err
+		}
+	}
+	tracing.InstrumentRedis(rdb)
+	hc.Register("redis", 0, health.Redis(rdb))
+	lc.Append(lifecycle.Hook{
+		Name:   "redis",
+		OnStop: func(context.Context) error { return rdb.Close() },
+	})
 
 	// 4. 构造 JWT 引擎，并初始化 jwt 中间件
-	jt := jwt.New(
-		cfg.JWT.Secret,
-		cfg.JWT.Issuer,
-		time.Duration(cfg.JWT.AccessExpire)*time.Second,
-		time.Duration(cfg.JWT.RefreshExpire)*time.Second,
-	)
+	jwtCfg, jwtErr := newJWTConfig(&cfg.JWT)
+	if jwtErr != nil {
+		return nil, jwtErr
+	}
+	jt, jwtErr := jwt.New(jwtCfg)
+	if jwtErr != nil {
+		return nil, jwtErr
+	}
 	middleware.InitJWT(jt) // jwt 中间件注入jwt引擎，避免每次调用都传入
 
 	// 5. 接管 Gin 内部日志、路由加载信息，全部转为 slog 形式（gin构造必须采用gin.New,且必须在gin.New()之前进行接管）
-	logger.BuilderGinLog(log)
+	logger.BuilderGinLog(logger.Component("gin"))
 
 	// 6. 构造 gin(使用干净的 Gin 引擎，方便接管日志以及其他中间件)
 	ge := gin.New()
+	// 客户端 IP 用于登录失败计数与 IP 锁定：只信任配置的代理转发的 X-Forwarded-For，避免伪造请求头绕过锁定或锁定他人
+	if err := ge.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("server.trusted_proxies: %w", err)
+	}
+	ge.Use(middleware.RequestID()) // 请求 ID 最先生成，其余中间件与业务日志均附带 request_id
+	if cfg.Metrics.Enabled {
+		ge.Use(middleware.Metrics()) // 0. 位于 Recovery 之外，panic 恢复后的 500 同样计入
+	}
 	ge.Use(
-		gin.Recovery(),    // 1. 最外层兜底
-		middleware.Cors(), // 2. 尽早处理 OPTIONS
-		middleware.Log(),  // 3. 正常请求日志
+		middleware.Trace(), // 0. 位于 Recovery 之外，panic 恢复后的 500 同样记录到 span；后续中间件日志均附带 trace_id
+		gin.Recovery(),     // 1. 最外层兜底
+		middleware.Cors(),  // 2. 尽早处理 OPTIONS
+		middleware.Log(),   // 3. 正常请求日志
 	)
+	if cfg.Log.Body.Enabled {
+		ge.Use(middleware.LogBody(cfg.Log.Body.MaxSize)) // 4. 请求 / 响应体日志（经脱敏输出）
+	}
+	ge.Use(middleware.Error()) // 5. 统一错误响应：handler 通过 c.Error(err) 返回的错误在此按类别输出
 
 	// 7. 构造 http.Server
 	se := &http.Server{
@@ -111,7 +206,31 @@ func NewApp(cfg *configs.Config) (*App, error) {
 	}
 	cm := cookie.NewCookieManager(cookiePkgCfg)
 
-	// 9. 构造 app
+	// 9. 构造邮件发送器
+	ml, mlErr := mail.New(mail.Config{
+		Driver: cfg.Mail.Driver,
+		From:   cfg.Mail.From,
+		Dir:    cfg.Mail.Dir,
+		SMTP: mail.SMTPConfig{
+			Host:     cfg.Mail.SMTP.Host,
+			Port:     cfg.Mail.SMTP.Port,
+			Username: cfg.Mail.SMTP.Username,
+			Password: cfg.Mail.SMTP.Password,
+			TLS:      cfg.Mail.SMTP.TLS,
+		},
+	})
+	if mlErr != nil {
+		return nil, mlErr
+	}
+	// 业务模块经队列异步发送邮件：停机时（HTTP 服务停止之后）发送完队列中剩余的邮件再退出
+	mq := mail.NewQueue(ml, mail.DefaultQueueSize)
+	lc.Go("mail", mq.Run)
+
+	// 10. 构造 app
+	shutdownTimeout := time.Duration(cfg.Server.ShutdownTimeout) * time.Second
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
 	app := &App{
 		Log: log,
 		Db:  db,
@@ -120,6 +239,123 @@ func NewApp(cfg *configs.Config) (*App, error) {
 		Ge:  ge,
 		Se:  se,
 		Cm:  cm,
+		Ml:  mq,
+		Lc:  lc,
+		Hc:  hc,
+
+		shutdownTimeout: shutdownTimeout,
+		shutdownDelay:   time.Duration(cfg.Server.ShutdownDelay) * time.Second,
+		serveErr:        make(chan error, 1),
 	}
 	return app, nil
 }
+
+// Run 启动 HTTP 服务与已注册的后台任务并阻塞，直到收到 SIGINT / SIGTERM 或 HTTP 服务异常退出，
+// 随后在 shutdownTimeout 内按注册的逆序停止：/readyz 置为未就绪（等待 shutdownDelay）-> HTTP 服务（不再接受新连接，等待进行中的请求完成）-> 后台任务 -> redis -> 数据库
+// 停机过程中再次收到信号时进程立即退出
+func (a *App) Run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// HTTP 服务最后注册、最先停止
+	a.Lc.Append(lifecycle.Hook{
+		Name:    "http",
+		OnStart: a.serve,
+		OnStop:  a.Se.Shutdown,
+	})
+	// 就绪状态在全部组件启动后置为就绪，停机时最先置为未就绪，负载均衡摘除流量后再停止 HTTP 服务
+	a.Lc.Append(lifecycle.Hook{
+		Name: "readiness",
+		OnStart: func(context.Context) error {
+			a.Hc.SetReady(true)
+			return nil
+		},
+		OnStop: a.drain,
+	})
+	if err := a.Lc.Start(ctx); err != nil {
+		return err
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		slog.Info("收到退出信号，开始优雅停机", "timeout", a.shutdownTimeout)
+	case runErr = <-a.serveErr:
+		slog.Error("HTTP 服务异常退出，开始停机", "error", runErr.Error())
+	}
+	stop() // 恢复默认信号处理：再次收到信号时立即退出
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout)
+	defer cancel()
+	if err := a.Lc.Stop(stopCtx); err != nil {
+		return errors.Join(runErr, err)
+	}
+	slog.Info("服务已停止")
+	return runErr
+}
+
+// serve 同步监听端口（端口占用等错误直接返回），在后台处理请求
+func (a *App) serve(ctx context.Context) error {
+	ln, err := (&net.ListenConfig{}).Listen(ctx, "tcp", a.Se.Addr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := a.Se.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			a.serveErr <- err
+		}
+	}()
+	return nil
+}
+
+// drain 置为未就绪，并等待 shutdownDelay 使负载均衡感知（期间仍正常处理请求）
+func (a *App) drain(ctx context.Context) error {
+	a.Hc.SetReady(false)
+	if a.shutdownDelay <= 0 {
+		return nil
+	}
+	t := time.NewTimer(a.shutdownDelay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// newJWTConfig 将配置转换为 jwt 引擎配置；未配置 keys 时退化为单个 HS256 密钥（兼容旧配置）
+func newJWTConfig(c *configs.JWT) (jwt.Config, error) {
+	out := jwt.Config{
+		Issuer:        c.Issuer,
+		AccessExpire:  time.Duration(c.AccessExpire) * time.Second,
+		RefreshExpire: time.Duration(c.RefreshExpire) * time.Second,
+		ActiveKID:     c.ActiveKID,
+		GracePeriod:   time.Duration(c.KeyGracePeriod) * time.Second,
+	}
+
+	if len(c.Keys) == 0 {
+		out.ActiveKID = "default"
+		out.Keys = []jwt.KeyConfig{{KID: "default", Algorithm: jwt.AlgHS256, Secret: c.Secret}}
+		return out, nil
+	}
+
+	for _, k := range c.Keys {
+		kc := jwt.KeyConfig{
+			KID:            k.KID,
+			Algorithm:      k.Algorithm,
+			Secret:         k.Secret,
+			PrivateKeyFile: k.PrivateKeyFile,
+			PublicKeyFile:  k.PublicKeyFile,
+		}
+		if k.RetiredAt != "" {
+			t, err := time.Parse(time.RFC3339, k.RetiredAt)
+			if err != nil {
+				return out, fmt.Errorf("jwt 密钥 %s 的 retired_at 格式错误: %w", k.KID, err)
+			}
+			kc.RetiredAt = t
+		}
+		out.Keys = append(out.Keys, kc)
+	}
+	return out, nil
+}
@@ -2,10 +2,23 @@ package boot
 
 import (
 	_ "mall-api/api/openapi"
+	"mall-api/configs"
 	"mall-api/internal/app/admin/iam/auth"
+	"mall-api/internal/app/admin/iam/menu"
+	"mall-api/internal/app/admin/iam/role"
+	"mall-api/internal/app/admin/identity"
+	"mall-api/internal/app/admin/system"
 	"mall-api/internal/app/admin/user"
 	"mall-api/internal/pkg/cookie"
+	"mall-api/internal/pkg/health"
 	"mall-api/internal/pkg/jwt"
+	"mall-api/internal/pkg/lifecycle"
+	"mall-api/internal/pkg/mail"
+	"mall-api/internal/pkg/metrics"
+	"mall-api/internal/pkg/middleware"
+	"mall-api/internal/pkg/password"
+	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
@@ -14,14 +27,74 @@ import (
 	"gorm.io/gorm"
 )
 
-func Register(r *gin.Engine, db *gorm.DB, rdb *redis.Client, jt *jwt.JWT, cm *cookie.CookieManager) {
+func Register(r *gin.Engine, db *gorm.DB, rdb *redis.Client, jt *jwt.JWT, cm *cookie.CookieManager, ml mail.Mailer, lc *lifecycle.Lifecycle, hc *health.Health, cfg *configs.Config) {
 	// openapi routes
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// 探针：存活 / 就绪（非统一响应格式，按 HTTP 状态码判断）
+	r.GET("/healthz", hc.Liveness)
+	r.GET("/readyz", hc.Readiness)
+
+	// Prometheus 指标（非统一响应格式）
+	if cfg.Metrics.Enabled {
+		path := cfg.Metrics.Path
+		if path == "" {
+			path = "/metrics"
+		}
+		r.GET(path, metrics.Handler(cfg.Metrics.Token))
+	}
+
+	// 公钥发布：其他服务据此验证本服务签发的 token（仅包含 RS256 / EdDSA 公钥）
+	r.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, jt.JWKS())
+	})
+
+	// 密码策略：auth（注册、修改密码）与 user（创建用户）共用
+	passwordPolicy := PasswordPolicy(&cfg.Auth.Password)
+
 	// admin routes
 	adminGroup := r.Group("/admin")
 	{
-		auth.Register(adminGroup, db, rdb, jt, cm)
-		user.Register(adminGroup, db)
+		accounts := identity.NewStore(db) // 用户表唯一的数据访问入口，auth 与 user 共用
+		roles := role.Register(adminGroup, db, rdb)
+		middleware.InitPermission(roles) // 权限中间件注入权限校验能力
+		authn := auth.Register(adminGroup, db, rdb, jt, cm, ml, accounts, roles, auth.Config{
+			MaxSessions:        cfg.Auth.MaxSessions,
+			AllowRegister:      cfg.Auth.AllowRegister,
+			LoginMaxFailures:   cfg.Auth.LoginMaxFailures,
+			LoginIPMaxFailures: cfg.Auth.LoginIPMaxFailures,
+			LoginFailureWindow: time.Duration(cfg.Auth.LoginFailureWindow) * time.Second,
+			LoginLockDuration:  time.Duration(cfg.Auth.LoginLockDuration) * time.Second,
+			LoginDelayStep:     time.Duration(cfg.Auth.LoginDelayStep) * time.Millisecond,
+			MFAIssuer:          cfg.App.Name,
+			PasswordPolicy:     passwordPolicy,
+			PasswordResetTTL:   time.Duration(cfg.Auth.PasswordResetTTL) * time.Second,
+			PasswordResetURL:   cfg.Auth.PasswordResetURL,
+			AppName:            cfg.App.Name,
+		})
+		middleware.InitRevocation(authn)    // jwt 中间件注入令牌吊销校验
+		middleware.InitAccountStatus(authn) // jwt 中间件注入账号状态校验
+		menu.Register(adminGroup, db, roles)
+		purgeJob := user.Register(adminGroup, accounts, roles, authn, user.Config{
+			PasswordPolicy:   passwordPolicy,
+			RecycleRetention: time.Duration(cfg.User.RecycleRetention) * time.Second,
+			PurgeInterval:    time.Duration(cfg.User.PurgeInterval) * time.Second,
+		})
+		lc.Go("user.purge", purgeJob.Run) // 回收站清理：彻底删除超过保留期的已删除用户
+		system.Register(adminGroup)       // 运维：运行时日志级别
+	}
+}
+
+// PasswordPolicy 由配置构造密码策略（server 与 cmd/seed 共用）
+func PasswordPolicy(c *configs.Password) password.Policy {
+	return password.Policy{
+		MinLength:     c.MinLength,
+		MaxLength:     c.MaxLength,
+		RequireUpper:  c.RequireUpper,
+		RequireLower:  c.RequireLower,
+		RequireDigit:  c.RequireDigit,
+		RequireSymbol: c.RequireSymbol,
+		History:       c.History,
 	}
 }
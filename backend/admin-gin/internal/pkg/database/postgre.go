@@ -6,6 +6,7 @@ import (
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 	"gorm.io/gorm/schema"
 )
 
@@ -31,6 +32,9 @@ type PostgreConfig struct {
 	MaxOpenConns int
 	// 连接最大生存时间
 	ConnMaxLifetime int
+
+	// 日志适配器，为空时使用 gorm 默认 logger（输出到标准输出）
+	Logger logger.Interface
 }
 
 func NewPostgre(c *PostgreConfig) (*gorm.DB, error) {
@@ -43,6 +47,7 @@ func NewPostgre(c *PostgreConfig) (*gorm.DB, error) {
 		NamingStrategy: schema.NamingStrategy{
 			SingularTable: true, // 使用单数表名，必须显示指定，不然gorm默认会使用复数表名
 		},
+		Logger: c.Logger,
 	})
 	if err != nil {
 		return nil, err
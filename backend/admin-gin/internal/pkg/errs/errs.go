@@ -0,0 +1,111 @@
+package errs
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kind 错误类别，决定返回给客户端的状态码
+type Kind int
+
+const (
+	KindInternal     Kind = iota // 内部错误：不向客户端暴露错误信息
+	KindBadRequest               // 请求参数错误
+	KindUnauthorized             // 未认证 / 认证失败
+	KindForbidden                // 无权操作
+	KindNotFound                 // 资源不存在
+	KindConflict                 // 资源冲突（已存在、被占用）
+	KindValidation               // 业务校验失败
+	KindRateLimited              // 请求过于频繁 / 被临时锁定
+)
+
+// Status 类别对应的 HTTP 状态码（写入响应体 code 字段）
+func (k Kind) Status() int {
+	switch k {
+	case KindBadRequest:
+		return http.StatusBadRequest
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindValidation:
+		return http.StatusUnprocessableEntity
+	case KindRateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// FieldError 字段校验失败明细
+type FieldError struct {
+	Field   string `json:"field" example:"email"`     // 字段名（JSON 字段名）
+	Message string `json:"message" example:"邮箱格式不正确"` // 失败原因
+}
+
+// Error 带类别的领域错误，Message 可直接返回给客户端
+// 模块以包级变量定义哨兵错误（如 errs.NotFound("用户不存在")），调用方通过 errors.Is 判断
+type Error struct {
+	Kind    Kind
+	Message string
+	Fields  []FieldError // 字段校验明细，仅 KindBadRequest / KindValidation 使用
+	cause   error
+}
+
+func (e *Error) Error() string { return e.Message }
+
+func (e *Error) Unwrap() error { return e.cause }
+
+func newError(kind Kind, msg string, fields ...FieldError) *Error {
+	return &Error{Kind: kind, Message: msg, Fields: fields}
+}
+
+// BadRequest 请求参数错误（400）
+func BadRequest(msg string, fields ...FieldError) *Error {
+	return newError(KindBadRequest, msg, fields...)
+}
+
+// Unauthorized 未认证 / 认证失败（401）
+func Unauthorized(msg string) *Error { return newError(KindUnauthorized, msg) }
+
+// Forbidden 无权操作（403）
+func Forbidden(msg string) *Error { return newError(KindForbidden, msg) }
+
+// NotFound 资源不存在（404）
+func NotFound(msg string) *Error { return newError(KindNotFound, msg) }
+
+// Conflict 资源冲突（409）
+func Conflict(msg string) *Error { return newError(KindConflict, msg) }
+
+// Validation 业务校验失败（422），可附带字段明细
+func Validation(msg string, fields ...FieldError) *Error {
+	return newError(KindValidation, msg, fields...)
+}
+
+// RateLimited 请求过于频繁 / 被临时锁定（429）
+func RateLimited(msg string) *Error { return newError(KindRateLimited, msg) }
+
+// Wrap 以指定类别包装 err，err.Error() 作为返回给客户端的信息，errors.Is 仍可匹配 err
+// 仅用于信息可公开的错误，例如同一哨兵错误在不同场景下需要不同的状态码、第三方包的可公开错误
+func Wrap(kind Kind, err error) *Error {
+	return &Error{Kind: kind, Message: err.Error(), cause: err}
+}
+
+// As 取出错误链中的 *Error
+func As(err error) (*Error, bool) {
+	var e *Error
+	ok := errors.As(err, &e)
+	return e, ok
+}
+
+// KindOf 错误类别，非 *Error 时为 KindInternal
+func KindOf(err error) Kind {
+	if e, ok := As(err); ok {
+		return e.Kind
+	}
+	return KindInternal
+}
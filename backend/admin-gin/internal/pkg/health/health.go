@@ -0,0 +1,158 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"mall-api/internal/pkg/logger"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+var log = logger.Component("health")
+
+// DefaultTimeout 单项检查默认超时时间
+const DefaultTimeout = 2 * time.Second
+
+// Check 依赖检查：返回 nil 表示依赖可用，须在 ctx 超时后尽快返回
+type Check func(ctx context.Context) error
+
+// Result 单项检查结果
+type Result struct {
+	Status   string `json:"status"` // ok / fail
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"` // 固定的失败原因，原始错误（含地址、驱动信息）只写入日志
+}
+
+// Report 就绪检查结果
+type Report struct {
+	Status string            `json:"status"` // ok / unavailable / not_ready（启动未完成或正在停机）
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+type entry struct {
+	name    string
+	timeout time.Duration
+	check   Check
+}
+
+// Health 存活与就绪探针：/healthz 仅表示进程存活，/readyz 并发检查全部已注册的依赖
+// 就绪状态由生命周期控制：启动完成后置为就绪，停机开始时先置为未就绪，使负载均衡先摘除流量
+type Health struct {
+	mu      sync.RWMutex
+	entries []entry
+	timeout time.Duration // 未单独指定超时的检查使用
+	ready   atomic.Bool
+}
+
+// New timeout 为单项检查的默认超时，<=0 时取 DefaultTimeout
+func New(timeout time.Duration) *Health {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Health{timeout: timeout}
+}
+
+// Register 注册依赖检查，timeout<=0 时使用默认超时；同名检查会被覆盖
+func (h *Health) Register(name string, timeout time.Duration, check Check) {
+	if timeout <= 0 {
+		timeout = h.timeout
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i := range h.entries {
+		if h.entries[i].name == name {
+			h.entries[i] = entry{name: name, timeout: timeout, check: check}
+			return
+		}
+	}
+	h.entries = append(h.entries, entry{name: name, timeout: timeout, check: check})
+}
+
+// SetReady 设置就绪状态，未就绪时 /readyz 直接返回 503，不执行依赖检查
+func (h *Health) SetReady(ready bool) {
+	h.ready.Store(ready)
+}
+
+// Check 并发执行全部依赖检查，每项检查使用独立的超时
+func (h *Health) Check(ctx context.Context) Report {
+	if !h.ready.Load() {
+		return Report{Status: "not_ready"}
+	}
+
+	h.mu.RLock()
+	entries := h.entries
+	h.mu.RUnlock()
+
+	results := make([]Result, len(entries))
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		wg.Go(func() {
+			results[i] = run(ctx, e)
+		})
+	}
+	wg.Wait()
+
+	report := Report{Status: "ok", Checks: make(map[string]Result, len(entries))}
+	for i, e := range entries {
+		if results[i].Status != "ok" {
+			report.Status = "unavailable"
+		}
+		report.Checks[e.name] = results[i]
+	}
+	return report
+}
+
+func run(ctx context.Context, e entry) Result {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := e.check(ctx)
+	res := Result{Status: "ok", Duration: time.Since(start).Round(time.Microsecond).String()}
+	switch {
+	case err == nil:
+	case errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil:
+		res.Status, res.Error = "fail", "检查超时（"+e.timeout.String()+"）"
+	default:
+		res.Status, res.Error = "fail", "检查失败"
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "依赖检查失败", "check", e.name, "duration", time.Since(start), "error", err)
+	}
+	return res
+}
+
+// Liveness 存活探针：进程能处理请求即返回 200，不检查依赖（依赖故障时重启进程无济于事）
+func (h *Health) Liveness(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readiness 就绪探针：全部依赖可用时返回 200，否则返回 503 及各项检查明细
+func (h *Health) Readiness(c *gin.Context) {
+	report := h.Check(c.Request.Context())
+	status := http.StatusOK
+	if report.Status != "ok" {
+		status = http.StatusServiceUnavailable
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(status, report)
+}
+
+// SQL 数据库连通性检查
+func SQL(db *sql.DB) Check {
+	return db.PingContext
+}
+
+// Redis redis 连通性检查
+func Redis(rdb redis.UniversalClient) Check {
+	return func(ctx context.Context) error {
+		return rdb.Ping(ctx).Err()
+	}
+}
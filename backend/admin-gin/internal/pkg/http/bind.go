@@ -0,0 +1,81 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mall-api/internal/pkg/errs"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// 校验错误中的字段名使用 json / form / uri 标签名，与前端提交的字段一致
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(f reflect.StructField) string {
+			for _, tag := range []string{"json", "form", "uri"} {
+				name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+				if name == "-" {
+					return ""
+				}
+				if name != "" {
+					return name
+				}
+			}
+			return f.Name
+		})
+	}
+}
+
+// BindError 将 ShouldBind* 的错误转为请求参数错误（400），校验失败时附带字段明细
+func BindError(err error) error {
+	var (
+		ves validator.ValidationErrors
+		ute *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &ves):
+		fields := make([]errs.FieldError, len(ves))
+		for i, fe := range ves {
+			fields[i] = errs.FieldError{Field: fe.Field(), Message: fieldMessage(fe)}
+		}
+		return errs.BadRequest(StatusText(http.StatusBadRequest), fields...)
+	case errors.As(err, &ute) && ute.Field != "":
+		return errs.BadRequest(StatusText(http.StatusBadRequest), errs.FieldError{Field: ute.Field, Message: "类型不正确"})
+	default:
+		return errs.BadRequest(StatusText(http.StatusBadRequest))
+	}
+}
+
+// fieldMessage 常用校验规则的提示信息
+func fieldMessage(fe validator.FieldError) string {
+	length := fe.Kind() == reflect.String || fe.Kind() == reflect.Slice || fe.Kind() == reflect.Map
+	switch fe.Tag() {
+	case "required":
+		return "不能为空"
+	case "email":
+		return "邮箱格式不正确"
+	case "ip":
+		return "IP 格式不正确"
+	case "oneof":
+		return fmt.Sprintf("取值必须为 %s 之一", strings.Join(strings.Fields(fe.Param()), " / "))
+	case "max", "lte":
+		if length {
+			return fmt.Sprintf("长度不能超过 %s", fe.Param())
+		}
+		return fmt.Sprintf("不能大于 %s", fe.Param())
+	case "min", "gte":
+		if length {
+			return fmt.Sprintf("长度不能少于 %s", fe.Param())
+		}
+		return fmt.Sprintf("不能小于 %s", fe.Param())
+	case "len":
+		return fmt.Sprintf("长度必须为 %s", fe.Param())
+	default:
+		return fmt.Sprintf("格式不正确（%s）", fe.Tag())
+	}
+}
@@ -1,11 +1,24 @@
 package http
 
 import (
+	"mall-api/internal/pkg/logger"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
+// statusKey gin 上下文中记录失败响应业务状态码的键
+const statusKey = "pkghttp:status"
+
+// Status 请求的业务状态码：失败响应的 HTTP 状态仍为 200，返回 FailWithData 记录的 code；
+// 其他情况（成功响应、panic 恢复后的 500 等）返回实际写出的 HTTP 状态码。供日志、指标、链路追踪中间件使用
+func Status(c *gin.Context) int {
+	if code := c.GetInt(statusKey); code != 0 {
+		return code
+	}
+	return c.Writer.Status()
+}
+
 // 普通成功响应
 func OK[T any](c *gin.Context, data T) {
 	c.JSON(http.StatusOK, HttpResponse[T]{
@@ -29,12 +42,18 @@ func Fail(c *gin.Context, code int, msg ...string) {
 	if len(msg) > 0 && msg[0] != "" {
 		displayMsg = msg[0]
 	}
+	FailWithData(c, code, displayMsg, nil)
+}
 
+// 失败响应并附带明细（如字段校验错误），data 为 nil 时不返回 data 字段
+func FailWithData(c *gin.Context, code int, msg string, data any) {
 	// 失败后，handler 中断，不再走下面的业务
-	// 注意：这里保持原行为，HTTP 响应状态仍返回 200，真实状态放在 JSON 的 code 字段中
+	// 注意：这里保持原行为，HTTP 响应状态仍返回 200，真实状态放在 JSON 的 code 字段中，并记录到上下文供 Status 读取
+	c.Set(statusKey, code)
 	c.AbortWithStatusJSON(http.StatusOK, HttpResponse[any]{
-		Code:    code,
-		Message: displayMsg,
-		Data:    nil,
+		Code:      code,
+		Message:   msg,
+		Data:      data,
+		RequestID: logger.RequestID(c.Request.Context()),
 	})
 }
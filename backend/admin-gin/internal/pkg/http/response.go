@@ -7,6 +7,8 @@ type HttpResponse[T any] struct {
 	Message string `json:"message" example:"操作成功"`
 	// data: 响应数据（可以为空）
 	Data T `json:"data,omitempty"`
+	// request_id: 请求 ID（仅失败响应返回，与响应头 X-Request-ID 一致），反馈问题时提供以便排查日志
+	RequestID string `json:"request_id,omitempty" example:"9f1c2d3e4b5a69788796a5b4c3d2e1f0"`
 }
 
 // 分页包装结构体
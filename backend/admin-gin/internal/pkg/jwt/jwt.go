@@ -2,9 +2,12 @@ package jwt
 
 import (
 	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 var (
@@ -12,10 +15,17 @@ var (
 	ErrExpiredToken = errors.New("令牌过期")
 )
 
+// iat / exp / nbf 精确到毫秒（RFC 7519 允许非整数的 NumericDate）：
+// 用户级吊销按签发时间判断，秒级精度会使吊销同一秒内重新登录签发的 token 一并失效
+func init() {
+	jwt.TimePrecision = time.Millisecond
+}
+
 // Claims JWT声明结构
 type Claims struct {
-	UID       string `json:"uid"`        // 全局唯一用户标识
-	TokenType string `json:"token_type"` // "access" 或 "refresh"
+	UID       string `json:"uid"`           // 全局唯一用户标识
+	SID       string `json:"sid,omitempty"` // 会话标识：同一次登录签发的 access/refresh 共享
+	TokenType string `json:"token_type"`    // "access" 或 "refresh"
 	jwt.RegisteredClaims
 }
 
@@ -24,24 +34,73 @@ type TokenPair struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 	ExpiresAt    int64  `json:"expires_at"` // access token 过期时间戳
+	AccessJTI    string `json:"-"`          // access token 的 jti，用于吊销
+}
+
+// Config JWT 引擎配置
+type Config struct {
+	Issuer        string
+	AccessExpire  time.Duration
+	RefreshExpire time.Duration
+
+	ActiveKID   string        // 当前签名密钥的 kid
+	Keys        []KeyConfig   // 全部密钥：当前签名密钥 + 尚在宽限期内的退役密钥
+	GracePeriod time.Duration // 退役密钥继续验签的宽限期，0 时取 RefreshExpire（覆盖其签发的全部 token）
 }
 
 // JWT 处理引擎
 type JWT struct {
-	secret        []byte
 	issuer        string
 	accessExpire  time.Duration
 	refreshExpire time.Duration
+
+	active      *key            // 当前签名密钥
+	keys        map[string]*key // kid -> 密钥，用于验签
+	methods     []string        // 允许的签名算法，防止算法混淆攻击
+	gracePeriod time.Duration
 }
 
 // New 创建 JWT 实例
-func New(secret, issuer string, accessExpire, refreshExpire time.Duration) *JWT {
-	return &JWT{
-		secret:        []byte(secret),
-		issuer:        issuer,
-		accessExpire:  accessExpire,
-		refreshExpire: refreshExpire,
+func New(cfg Config) (*JWT, error) {
+	j := &JWT{
+		issuer:        cfg.Issuer,
+		accessExpire:  cfg.AccessExpire,
+		refreshExpire: cfg.RefreshExpire,
+		keys:          make(map[string]*key, len(cfg.Keys)),
+		gracePeriod:   cfg.GracePeriod,
+	}
+	if j.gracePeriod <= 0 {
+		j.gracePeriod = cfg.RefreshExpire
+	}
+
+	for _, kc := range cfg.Keys {
+		k, err := loadKey(kc)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := j.keys[k.kid]; ok {
+			return nil, fmt.Errorf("jwt: 密钥 kid %s 重复", k.kid)
+		}
+		j.keys[k.kid] = k
+		if !slices.Contains(j.methods, k.method.Alg()) {
+			j.methods = append(j.methods, k.method.Alg())
+		}
+	}
+
+	// 校验签名密钥：必须存在、持有私钥且未退役
+	active, ok := j.keys[cfg.ActiveKID]
+	if !ok {
+		return nil, fmt.Errorf("jwt: 未找到签名密钥 %q", cfg.ActiveKID)
+	}
+	if active.signKey == nil {
+		return nil, fmt.Errorf("jwt: 签名密钥 %s 缺少私钥", active.kid)
 	}
+	if !active.retiredAt.IsZero() {
+		return nil, fmt.Errorf("jwt: 签名密钥 %s 已退役", active.kid)
+	}
+	j.active = active
+
+	return j, nil
 }
 
 // GetAccessExpire 获取 Access Token 过期时间
@@ -55,18 +114,24 @@ func (j *JWT) GetRefreshExpire() time.Duration {
 }
 
 // GenerateToken 签发指定类型的 Token (用于刷新场景，可指定自定义有效期)
-func (j *JWT) GenerateToken(uid string, tokenType string, expireDuration time.Duration) (string, error) {
-	return j.generateToken(uid, tokenType, expireDuration)
+func (j *JWT) GenerateToken(uid, sid string, tokenType string, expireDuration time.Duration) (string, error) {
+	token, _, err := j.generateToken(uid, sid, tokenType, expireDuration)
+	return token, err
+}
+
+// GenerateTokenPair 生成一对 Token (access + refresh)，sid 为所属会话标识
+func (j *JWT) GenerateTokenPair(uid, sid string) (*TokenPair, error) {
+	return j.RenewTokenPair(uid, sid, j.refreshExpire)
 }
 
-// GenerateTokenPair 生成一对 Token (access + refresh)
-func (j *JWT) GenerateTokenPair(uid string) (*TokenPair, error) {
-	accessToken, err := j.generateToken(uid, "access", j.accessExpire)
+// RenewTokenPair 续签 Token 对：access 总是给满额有效期，refresh 使用指定有效期（刷新场景传入旧 refresh 的剩余寿命）
+func (j *JWT) RenewTokenPair(uid, sid string, refreshExpire time.Duration) (*TokenPair, error) {
+	accessToken, accessClaims, err := j.generateToken(uid, sid, "access", j.accessExpire)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := j.generateToken(uid, "refresh", j.refreshExpire)
+	refreshToken, _, err := j.generateToken(uid, sid, "refresh", refreshExpire)
 	if err != nil {
 		return nil, err
 	}
@@ -74,16 +139,15 @@ func (j *JWT) GenerateTokenPair(uid string) (*TokenPair, error) {
 	return &TokenPair{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
-		ExpiresAt:    time.Now().Add(j.accessExpire).Unix(),
+		ExpiresAt:    accessClaims.ExpiresAt.Unix(),
+		AccessJTI:    accessClaims.ID,
 	}, nil
 }
 
 // ParseToken 解析并验证 JWT token
 // 如果 tokenType 不为空，则会额外校验声明中的 token_type 字段是否匹配 ("access" 或 "refresh")
 func (j *JWT) ParseToken(tokenString string, tokenType string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return j.secret, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, j.keyFunc, jwt.WithValidMethods(j.methods))
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -93,7 +157,7 @@ func (j *JWT) ParseToken(tokenString string, tokenType string) (*Claims, error)
 	}
 
 	claims, ok := token.Claims.(*Claims)
-	if !ok || !token.Valid {
+	if !ok || !token.Valid || claims.ID == "" {
 		return nil, ErrInvalidToken
 	}
 
@@ -105,13 +169,15 @@ func (j *JWT) ParseToken(tokenString string, tokenType string) (*Claims, error)
 	return claims, nil
 }
 
-// token 生成通用函数
-func (j *JWT) generateToken(uid string, tokenType string, expireDuration time.Duration) (string, error) {
+// token 生成通用函数，每个 token 携带唯一 jti（RegisteredClaims.ID），用于吊销
+func (j *JWT) generateToken(uid, sid string, tokenType string, expireDuration time.Duration) (string, *Claims, error) {
 	now := time.Now()
 	claims := Claims{
 		UID:       uid,
+		SID:       sid,
 		TokenType: tokenType,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			Issuer:    j.issuer,
 			ExpiresAt: jwt.NewNumericDate(now.Add(expireDuration)),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -119,5 +185,27 @@ func (j *JWT) generateToken(uid string, tokenType string, expireDuration time.Du
 		},
 	}
 
-	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(j.secret)
+	t := jwt.NewWithClaims(j.active.method, claims)
+	t.Header["kid"] = j.active.kid
+	token, err := t.SignedString(j.active.signKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, &claims, nil
+}
+
+// keyFunc 按 token 头部的 kid 选择验签密钥，并校验算法与密钥匹配、密钥未超出退役宽限期
+func (j *JWT) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	k, ok := j.keys[kid]
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	if token.Method.Alg() != k.method.Alg() {
+		return nil, ErrInvalidToken
+	}
+	if !k.canVerify(time.Now(), j.gracePeriod) {
+		return nil, ErrInvalidToken
+	}
+	return k.verifyKey, nil
 }
@@ -0,0 +1,180 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"maps"
+	"math/big"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 支持的签名算法
+const (
+	AlgHS256 = "HS256" // 对称：共享密钥，仅本服务可验签
+	AlgRS256 = "RS256" // 非对称：RSA，公钥可通过 JWKS 对外发布
+	AlgEdDSA = "EdDSA" // 非对称：Ed25519，公钥可通过 JWKS 对外发布
+)
+
+// KeyConfig 单个签名密钥配置
+type KeyConfig struct {
+	KID       string // 密钥标识，写入 token 头部 kid
+	Algorithm string // HS256 / RS256 / EdDSA
+
+	Secret         string // HS256 共享密钥
+	PrivateKeyFile string // RS256 / EdDSA 私钥 PEM 文件（签名用，退役密钥可不配置）
+	PublicKeyFile  string // RS256 / EdDSA 公钥 PEM 文件（未配置时由私钥推导）
+
+	RetiredAt time.Time // 退役时间：零值表示未退役；退役后不再签名，宽限期内仍可验签
+}
+
+// key 已加载的密钥
+type key struct {
+	kid       string
+	method    jwt.SigningMethod
+	signKey   any // []byte / *rsa.PrivateKey / ed25519.PrivateKey，nil 表示仅可验签
+	verifyKey any // []byte / *rsa.PublicKey / ed25519.PublicKey
+	retiredAt time.Time
+}
+
+// loadKey 按配置加载密钥
+func loadKey(cfg KeyConfig) (*key, error) {
+	if cfg.KID == "" {
+		return nil, errors.New("jwt: 密钥 kid 不能为空")
+	}
+
+	k := &key{kid: cfg.KID, retiredAt: cfg.RetiredAt}
+	switch cfg.Algorithm {
+	case AlgHS256:
+		if cfg.Secret == "" {
+			return nil, fmt.Errorf("jwt: 密钥 %s 缺少 secret", cfg.KID)
+		}
+		k.method = jwt.SigningMethodHS256
+		k.signKey = []byte(cfg.Secret)
+		k.verifyKey = []byte(cfg.Secret)
+
+	case AlgRS256:
+		k.method = jwt.SigningMethodRS256
+		if cfg.PrivateKeyFile != "" {
+			pem, err := os.ReadFile(cfg.PrivateKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("jwt: 读取密钥 %s 私钥失败: %w", cfg.KID, err)
+			}
+			priv, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, fmt.Errorf("jwt: 解析密钥 %s 私钥失败: %w", cfg.KID, err)
+			}
+			k.signKey = priv
+			k.verifyKey = &priv.PublicKey
+		}
+		if cfg.PublicKeyFile != "" {
+			pem, err := os.ReadFile(cfg.PublicKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("jwt: 读取密钥 %s 公钥失败: %w", cfg.KID, err)
+			}
+			pub, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+			if err != nil {
+				return nil, fmt.Errorf("jwt: 解析密钥 %s 公钥失败: %w", cfg.KID, err)
+			}
+			k.verifyKey = pub
+		}
+
+	case AlgEdDSA:
+		k.method = jwt.SigningMethodEdDSA
+		if cfg.PrivateKeyFile != "" {
+			pem, err := os.ReadFile(cfg.PrivateKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("jwt: 读取密钥 %s 私钥失败: %w", cfg.KID, err)
+			}
+			priv, err := jwt.ParseEdPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, fmt.Errorf("jwt: 解析密钥 %s 私钥失败: %w", cfg.KID, err)
+			}
+			k.signKey = priv
+			k.verifyKey = priv.(crypto.Signer).Public()
+		}
+		if cfg.PublicKeyFile != "" {
+			pem, err := os.ReadFile(cfg.PublicKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("jwt: 读取密钥 %s 公钥失败: %w", cfg.KID, err)
+			}
+			pub, err := jwt.ParseEdPublicKeyFromPEM(pem)
+			if err != nil {
+				return nil, fmt.Errorf("jwt: 解析密钥 %s 公钥失败: %w", cfg.KID, err)
+			}
+			k.verifyKey = pub
+		}
+
+	default:
+		return nil, fmt.Errorf("jwt: 密钥 %s 使用了不支持的算法 %q", cfg.KID, cfg.Algorithm)
+	}
+
+	if k.verifyKey == nil {
+		return nil, fmt.Errorf("jwt: 密钥 %s 至少需要配置私钥或公钥", cfg.KID)
+	}
+	return k, nil
+}
+
+// canVerify 密钥在 now 时刻是否仍可验签：未退役，或仍处于退役宽限期内
+func (k *key) canVerify(now time.Time, grace time.Duration) bool {
+	return k.retiredAt.IsZero() || now.Before(k.retiredAt.Add(grace))
+}
+
+// JWK 单个 JSON Web Key（RFC 7517），仅包含公钥参数
+type JWK struct {
+	KID string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n,omitempty"`   // RSA 模数
+	E   string `json:"e,omitempty"`   // RSA 指数
+	Crv string `json:"crv,omitempty"` // OKP 曲线
+	X   string `json:"x,omitempty"`   // OKP 公钥
+}
+
+// JWKS JSON Web Key Set，用于 /.well-known/jwks.json
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS 返回仍可验签的非对称公钥集合（HS256 共享密钥不对外发布）
+func (j *JWT) JWKS() JWKS {
+	now := time.Now()
+	set := JWKS{Keys: []JWK{}}
+	for _, kid := range slices.Sorted(maps.Keys(j.keys)) {
+		k := j.keys[kid]
+		if !k.canVerify(now, j.gracePeriod) {
+			continue
+		}
+
+		b64 := base64.RawURLEncoding.EncodeToString
+		switch pub := k.verifyKey.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				KID: k.kid,
+				Kty: "RSA",
+				Alg: AlgRS256,
+				Use: "sig",
+				N:   b64(pub.N.Bytes()),
+				E:   b64(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				KID: k.kid,
+				Kty: "OKP",
+				Alg: AlgEdDSA,
+				Use: "sig",
+				Crv: "Ed25519",
+				X:   b64(pub),
+			})
+		}
+	}
+	return set
+}
@@ -0,0 +1,124 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Hook 启停钩子：Start 时按注册顺序执行 OnStart，Stop 时按注册的逆序执行 OnStop
+// 先注册的资源（如数据库）后停止，保证依赖它的组件（如 HTTP 服务、后台任务）先停止
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error // 可为 nil
+	OnStop  func(ctx context.Context) error // 可为 nil
+}
+
+// Lifecycle 应用生命周期管理：统一启动与停止 HTTP 服务、后台任务以及数据库等资源
+// 钩子须在 Start 之前注册
+type Lifecycle struct {
+	mu      sync.Mutex
+	hooks   []Hook
+	started int // 已成功启动的钩子数量，Stop 只停止这些钩子
+}
+
+func New() *Lifecycle {
+	return &Lifecycle{}
+}
+
+// Append 注册启停钩子
+func (l *Lifecycle) Append(h Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, h)
+}
+
+// Go 注册后台任务：Start 时在独立 goroutine 中运行 run，Stop 时取消其 ctx 并等待退出（受 Stop 的 ctx 超时约束）
+// run 应在 ctx 取消后尽快返回
+func (l *Lifecycle) Go(name string, run func(ctx context.Context)) {
+	var (
+		cancel context.CancelFunc
+		done   chan struct{}
+	)
+	l.Append(Hook{
+		Name: name,
+		OnStart: func(context.Context) error {
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			done = make(chan struct{})
+			go func() {
+				defer close(done)
+				defer func() {
+					if r := recover(); r != nil {
+						slog.Error("后台任务异常退出", "worker", name, "panic", r)
+					}
+				}()
+				run(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return fmt.Errorf("等待后台任务退出超时: %w", ctx.Err())
+			}
+		},
+	})
+}
+
+// Start 按注册顺序启动；某个钩子启动失败时，逆序停止已启动的钩子并返回错误
+func (l *Lifecycle) Start(ctx context.Context) error {
+	l.mu.Lock()
+	hooks := l.hooks
+	l.mu.Unlock()
+
+	for i, h := range hooks {
+		if h.OnStart != nil {
+			if err := h.OnStart(ctx); err != nil {
+				err = fmt.Errorf("启动 %s 失败: %w", h.Name, err)
+				l.setStarted(i)
+				return errors.Join(err, l.Stop(context.WithoutCancel(ctx)))
+			}
+		}
+		slog.Debug("组件已启动", "component", h.Name)
+	}
+	l.setStarted(len(hooks))
+	return nil
+}
+
+// Stop 按注册的逆序停止已启动的钩子；单个钩子失败不影响其他钩子，错误合并返回
+// ctx 的截止时间为整个停止过程的总时长，超时后剩余钩子仍会执行（收到已过期的 ctx）
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	l.mu.Lock()
+	hooks := l.hooks[:l.started]
+	l.started = 0
+	l.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		if h.OnStop == nil {
+			continue
+		}
+		start := time.Now()
+		if err := h.OnStop(ctx); err != nil {
+			slog.Error("组件停止失败", "component", h.Name, "error", err.Error())
+			errs = append(errs, fmt.Errorf("停止 %s 失败: %w", h.Name, err))
+			continue
+		}
+		slog.Info("组件已停止", "component", h.Name, "cost", time.Since(start))
+	}
+	return errors.Join(errs...)
+}
+
+func (l *Lifecycle) setStarted(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.started = n
+}
@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"slices"
+	"sync/atomic"
+)
+
+// root 当前的输出 handler（格式、文件切割），由 NewLog 替换
+// 组件 logger 在输出时才解析 root，因此可以在包初始化阶段创建（如 var log = logger.Component("auth")）
+var root atomic.Pointer[rootHandler]
+
+type rootHandler struct {
+	h slog.Handler
+}
+
+func init() {
+	// NewLog 之前（如命令行工具、包初始化阶段）输出到标准错误，按默认规则脱敏
+	r, _ := newRedactor(RedactConfig{})
+	redaction.Store(r)
+	root.Store(&rootHandler{h: newRedactHandler(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}), r)})
+}
+
+// Component 返回组件 logger：日志附带 component 字段，按该组件的级别过滤（未单独配置时跟随全局默认级别）
+// 组件名与配置 log.levels 的 key 对应，如 auth、gorm、gin
+func Component(name string) *slog.Logger {
+	return slog.New(contextHandler{Handler: newLevelHandler(levels.leveler(name), slog.String("component", name))})
+}
+
+// levelHandler 按级别过滤后输出到 root；WithAttrs / WithGroup 记录为操作，在 root 变化后重新应用
+type levelHandler struct {
+	level slog.Leveler
+	ops   []func(slog.Handler) slog.Handler
+	cache atomic.Pointer[resolvedHandler]
+}
+
+type resolvedHandler struct {
+	root *rootHandler
+	h    slog.Handler
+}
+
+func newLevelHandler(level slog.Leveler, attrs ...slog.Attr) *levelHandler {
+	h := &levelHandler{level: level}
+	if len(attrs) > 0 {
+		h.ops = append(h.ops, func(next slog.Handler) slog.Handler { return next.WithAttrs(attrs) })
+	}
+	return h
+}
+
+func (h *levelHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= h.level.Level()
+}
+
+func (h *levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.resolve().Handle(ctx, r)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithAttrs(attrs) })
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithGroup(name) })
+}
+
+func (h *levelHandler) with(op func(slog.Handler) slog.Handler) *levelHandler {
+	return &levelHandler{level: h.level, ops: append(slices.Clip(h.ops), op)}
+}
+
+// resolve 将记录的操作应用到当前 root，结果缓存至 root 被替换为止
+func (h *levelHandler) resolve() slog.Handler {
+	rt := root.Load()
+	if c := h.cache.Load(); c != nil && c.root == rt {
+		return c.h
+	}
+	out := rt.h
+	for _, op := range h.ops {
+		out = op(out)
+	}
+	h.cache.Store(&resolvedHandler{root: rt, h: out})
+	return out
+}
@@ -1,11 +1,13 @@
 package logger
 
 type Config struct {
-	Level    string `mapstructure:"level"`
-	Format   string `mapstructure:"format"`
-	Dir      string `mapstructure:"director"`
-	Filename string `mapstructure:"filename"`
-	MaxSize  int    `mapstructure:"max_size"`
-	MaxAge   int    `mapstructure:"max_age"`
-	Compress bool   `mapstructure:"compress"`
+	Level    string            `mapstructure:"level"`
+	Levels   map[string]string `mapstructure:"levels"` // 组件级别，如 gorm: warn、auth: debug
+	Format   string            `mapstructure:"format"`
+	Dir      string            `mapstructure:"director"`
+	Filename string            `mapstructure:"filename"`
+	MaxSize  int               `mapstructure:"max_size"`
+	MaxAge   int               `mapstructure:"max_age"`
+	Compress bool              `mapstructure:"compress"`
+	Redact   RedactConfig      `mapstructure:"redact"`
 }
@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ctxKey int
+
+const (
+	requestIDKey ctxKey = iota
+	attrsKey
+)
+
+// WithRequestID 在 ctx 中记录请求 ID，经该 ctx 输出的日志自动附带 request_id
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestID 读取 ctx 中的请求 ID，不存在时返回空字符串
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// With 在 ctx 中追加日志字段（如 uid、route），经该 ctx 输出的日志（slog.*Context）自动附带
+// 返回新的 ctx，原 ctx 不受影响
+func With(ctx context.Context, attrs ...slog.Attr) context.Context {
+	prev, _ := ctx.Value(attrsKey).([]slog.Attr)
+	merged := make([]slog.Attr, 0, len(prev)+len(attrs))
+	merged = append(merged, prev...)
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, attrsKey, merged)
+}
+
+// contextHandler 从 ctx 中读取请求 ID、With 追加的字段以及 trace_id / span_id 并写入日志记录
+// 仅 *Context 系列方法（如 slog.InfoContext）传入的 ctx 有效
+// ctx 中的字段始终写在顶层：WithGroup 打开的分组由本 handler 记录，输出时只包裹调用方的字段，
+// 避免 request_id 等字段随调用方的分组嵌套，按顶层 request_id 查询日志时遗漏
+type contextHandler struct {
+	slog.Handler                // 未打开分组的下游 handler
+	groups       []groupOrAttrs // 首个 WithGroup 起依次记录的分组与字段
+}
+
+// groupOrAttrs WithGroup（group 非空）或 WithAttrs 的一次调用
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if len(h.groups) > 0 {
+		r = h.nest(r)
+	}
+	if ctx != nil {
+		if id := RequestID(ctx); id != "" {
+			r.AddAttrs(slog.String("request_id", id))
+		}
+		if attrs, ok := ctx.Value(attrsKey).([]slog.Attr); ok {
+			r.AddAttrs(attrs...)
+		}
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			r.AddAttrs(
+				slog.String("trace_id", sc.TraceID().String()),
+				slog.String("span_id", sc.SpanID().String()),
+			)
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// nest 将分组内的字段与记录本身的字段按分组层级包裹，返回只含顶层字段的新记录
+func (h contextHandler) nest(r slog.Record) slog.Record {
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		g := h.groups[i]
+		if g.group == "" {
+			attrs = append(slices.Clip(g.attrs), attrs...)
+			continue
+		}
+		if len(attrs) == 0 {
+			continue // 与 slog 内置 handler 一致：空分组不输出
+		}
+		attrs = []slog.Attr{{Key: g.group, Value: slog.GroupValue(attrs...)}}
+	}
+
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	out.AddAttrs(attrs...)
+	return out
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	if len(h.groups) == 0 {
+		return contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+	}
+	return contextHandler{Handler: h.Handler, groups: append(slices.Clip(h.groups), groupOrAttrs{attrs: attrs})}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return contextHandler{Handler: h.Handler, groups: append(slices.Clip(h.groups), groupOrAttrs{group: name})}
+}
@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// defaultSlowThreshold 慢查询默认阈值
+const defaultSlowThreshold = 200 * time.Millisecond
+
+// GormLogger gorm 日志适配器：SQL 经组件 logger "gorm" 输出，与应用日志使用同一 handler（格式、文件切割、级别）
+//
+//   - 执行失败（不含记录不存在）：error
+//   - 超过慢查询阈值：warn
+//   - 其余语句：debug（log.levels.gorm: debug 时可见）
+//
+// SQL 中的参数以占位符输出，不记录参数值（避免密码 hash、令牌等进入日志）
+type GormLogger struct {
+	log           *slog.Logger
+	slowThreshold time.Duration
+	mode          gormlogger.LogLevel // 由 db.Debug() / Session 设置，0 表示按组件级别过滤
+}
+
+// NewGormLogger slowThreshold<=0 时取 200ms
+func NewGormLogger(slowThreshold time.Duration) *GormLogger {
+	if slowThreshold <= 0 {
+		slowThreshold = defaultSlowThreshold
+	}
+	return &GormLogger{log: Component("gorm"), slowThreshold: slowThreshold}
+}
+
+// LogMode db.Debug() 传入 Info：该会话的全部 SQL 以 info 级别输出；Silent 不输出
+func (l *GormLogger) LogMode(mode gormlogger.LogLevel) gormlogger.Interface {
+	nl := *l
+	nl.mode = mode
+	return &nl
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, args ...any) {
+	l.print(ctx, slog.LevelInfo, gormlogger.Info, msg, args...)
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, args ...any) {
+	l.print(ctx, slog.LevelWarn, gormlogger.Warn, msg, args...)
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, args ...any) {
+	l.print(ctx, slog.LevelError, gormlogger.Error, msg, args...)
+}
+
+func (l *GormLogger) print(ctx context.Context, level slog.Level, need gormlogger.LogLevel, msg string, args ...any) {
+	if l.mode != 0 && l.mode < need {
+		return
+	}
+	l.log.Log(ctx, level, msg, "args", args)
+}
+
+// Trace 每条语句执行后调用
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.mode == gormlogger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+
+	var level slog.Level
+	msg := "sql"
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		level, msg = slog.LevelError, "sql error"
+	case elapsed > l.slowThreshold:
+		level, msg = slog.LevelWarn, "slow sql"
+	case l.mode == gormlogger.Info:
+		level = slog.LevelInfo
+	default:
+		level = slog.LevelDebug
+	}
+	if !l.log.Enabled(ctx, level) {
+		return // 未启用时不生成 SQL 字符串
+	}
+
+	sql, rows := fc()
+	attrs := []any{slog.String("sql", sql), slog.Duration("elapsed", elapsed), slog.Int64("rows", rows)}
+	if err != nil && level == slog.LevelError {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	if level == slog.LevelWarn {
+		attrs = append(attrs, slog.Duration("threshold", l.slowThreshold))
+	}
+	l.log.Log(ctx, level, msg, attrs...)
+}
+
+// ParamsFilter 丢弃参数值，SQL 以占位符形式输出
+func (l *GormLogger) ParamsFilter(_ context.Context, sql string, _ ...any) (string, []any) {
+	return sql, nil
+}
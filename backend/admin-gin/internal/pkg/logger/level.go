@@ -1,18 +1,150 @@
 package logger
 
-import "log/slog"
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
 
-func parseLevel(level string) slog.Level {
-	switch level {
+// DefaultComponent 全局默认级别：未单独配置级别的组件使用该级别
+const DefaultComponent = "default"
+
+// ParseLevel 解析日志级别：debug / info / warn(warning) / error，大小写不敏感，空字符串为 info
+// 不认识的级别返回错误，避免拼写错误被静默当作 info
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
 	case "debug":
-		return slog.LevelDebug
-	case "info":
-		return slog.LevelInfo
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
 	case "warn", "warning":
-		return slog.LevelWarn
+		return slog.LevelWarn, nil
 	case "error":
-		return slog.LevelError
+		return slog.LevelError, nil
 	default:
-		return slog.LevelInfo
+		return 0, fmt.Errorf("日志级别无效: %q（可选 debug / info / warn / error）", level)
+	}
+}
+
+// levelName 与 ParseLevel 对应的级别名称
+func levelName(l slog.Level) string {
+	return strings.ToLower(l.String())
+}
+
+// levelRegistry 全局默认级别与各组件级别，运行时可修改
+// 组件级别采用写时复制：记录日志时（Enabled）只做一次原子读取，不加锁
+type levelRegistry struct {
+	def   slog.LevelVar
+	mu    sync.Mutex // 串行化写操作，保护 known
+	comps atomic.Pointer[map[string]slog.Level]
+	known map[string]struct{} // 已通过 Component 创建 logger 的组件
+}
+
+var levels = newLevelRegistry()
+
+func newLevelRegistry() *levelRegistry {
+	r := &levelRegistry{known: make(map[string]struct{})}
+	r.comps.Store(&map[string]slog.Level{})
+	return r
+}
+
+// leveler 组件的生效级别：单独配置时使用组件级别，否则跟随全局默认级别
+func (r *levelRegistry) leveler(component string) slog.Leveler {
+	if component == "" || component == DefaultComponent {
+		return &r.def
+	}
+	r.mu.Lock()
+	r.known[component] = struct{}{}
+	r.mu.Unlock()
+	return componentLevel{r: r, name: component}
+}
+
+func (r *levelRegistry) set(component string, level slog.Level) {
+	if component == "" || component == DefaultComponent {
+		r.def.Set(level)
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	next := maps.Clone(*r.comps.Load())
+	next[component] = level
+	r.comps.Store(&next)
+}
+
+func (r *levelRegistry) reset(component string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	next := maps.Clone(*r.comps.Load())
+	delete(next, component)
+	r.comps.Store(&next)
+}
+
+type componentLevel struct {
+	r    *levelRegistry
+	name string
+}
+
+func (c componentLevel) Level() slog.Level {
+	if l, ok := (*c.r.comps.Load())[c.name]; ok {
+		return l
+	}
+	return c.r.def.Level()
+}
+
+// LevelState 当前生效的日志级别
+type LevelState struct {
+	Default    string           // 全局默认级别
+	Components []ComponentLevel // 已创建 logger 或单独配置了级别的组件（按名称排序）
+}
+
+// ComponentLevel 组件的生效级别
+type ComponentLevel struct {
+	Name       string
+	Level      string
+	Overridden bool // 是否单独配置了级别，false 表示跟随全局默认级别
+}
+
+// Levels 查询当前的全局默认级别与各组件的生效级别
+func Levels() LevelState {
+	levels.mu.Lock()
+	names := slices.Collect(maps.Keys(levels.known))
+	levels.mu.Unlock()
+
+	comps := *levels.comps.Load()
+	for name := range comps {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
 	}
+	slices.Sort(names)
+
+	def := levels.def.Level()
+	st := LevelState{Default: levelName(def), Components: make([]ComponentLevel, 0, len(names))}
+	for _, name := range names {
+		l, ok := comps[name]
+		if !ok {
+			l = def
+		}
+		st.Components = append(st.Components, ComponentLevel{Name: name, Level: levelName(l), Overridden: ok})
+	}
+	return st
+}
+
+// SetLevel 运行时修改日志级别，立即生效；component 为空或 default 时修改全局默认级别
+func SetLevel(component, level string) error {
+	l, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	levels.set(strings.TrimSpace(component), l)
+	return nil
+}
+
+// ResetLevel 移除组件单独配置的级别，使其跟随全局默认级别
+func ResetLevel(component string) {
+	levels.reset(strings.TrimSpace(component))
 }
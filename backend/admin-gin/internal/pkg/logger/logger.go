@@ -1,20 +1,41 @@
 package logger
 
 import (
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
 )
 
-func NewLog(cfg Config) *slog.Logger {
+// NewLog 构造全局 logger：输出到文件（按大小切割）与标准输出，按全局默认级别过滤；
+// 同时设置各组件的级别（Component 创建的 logger 使用）与脱敏规则，级别或脱敏配置有误时返回错误
+func NewLog(cfg Config) (*slog.Logger, error) {
+	def, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	comps := make(map[string]slog.Level, len(cfg.Levels))
+	for name, level := range cfg.Levels {
+		l, err := ParseLevel(level)
+		if err != nil {
+			return nil, fmt.Errorf("log.levels.%s: %w", name, err)
+		}
+		comps[name] = l
+	}
+	redactor, err := newRedactor(cfg.Redact)
+	if err != nil {
+		return nil, err
+	}
+
 	var writer io.Writer
 
 	fileWriter := newWriter(cfg)
 
 	writer = io.MultiWriter(fileWriter, os.Stdout)
 
+	// 底层 handler 不做级别过滤，由 levelHandler 按全局 / 组件级别过滤
 	opts := &slog.HandlerOptions{
-		Level: parseLevel(cfg.Level),
+		Level: slog.LevelDebug,
 	}
 
 	var handler slog.Handler
@@ -24,9 +45,17 @@ func NewLog(cfg Config) *slog.Logger {
 	default:
 		handler = slog.NewTextHandler(writer, opts)
 	}
+	// 所有 logger（全局、组件、gin、gorm）均经脱敏后输出
+	redaction.Store(redactor)
+	root.Store(&rootHandler{h: newRedactHandler(handler, redactor)})
+
+	levels.set(DefaultComponent, def)
+	for name, l := range comps {
+		levels.set(name, l)
+	}
 
-	logger := slog.New(handler)
+	logger := slog.New(contextHandler{Handler: newLevelHandler(levels.leveler(DefaultComponent))})
 	slog.SetDefault(logger)
 
-	return logger
+	return logger, nil
 }
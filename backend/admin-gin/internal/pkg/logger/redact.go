@@ -0,0 +1,224 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// Redacted 敏感字段脱敏后的取值
+const Redacted = "***"
+
+// 内置的脱敏模式
+const (
+	PatternToken = "token" // JWT 与 Bearer 令牌：整体替换为 ***
+	PatternEmail = "email" // 邮箱：保留首字符与域名，如 a***@example.com
+	PatternPhone = "phone" // 手机号：保留前 3 位与后 4 位，如 138****5678
+)
+
+// 默认脱敏的字段名（大小写不敏感，同时匹配以 _<名称> 结尾的字段，如 password 匹配 new_password）
+var defaultRedactFields = []string{
+	"password", "token", "secret", "authorization", "cookie",
+	"otpauth_url", "recovery_codes", "api_key",
+}
+
+// 默认脱敏的请求 / 响应头（大小写不敏感）
+var defaultRedactHeaders = []string{
+	"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "X-Auth-Token",
+}
+
+// RedactConfig 日志脱敏配置
+type RedactConfig struct {
+	Fields   []string // 追加脱敏的字段名（在默认列表基础上）
+	Headers  []string // 追加脱敏的请求 / 响应头（在默认列表基础上）
+	Patterns []string // 对字符串取值按模式打码：token / email / phone，为空时全部启用
+}
+
+// redaction 当前的脱敏规则，由 NewLog 替换；Headers 按其中的请求头列表脱敏
+var redaction atomic.Pointer[redactor]
+
+type redactor struct {
+	fields   []string
+	headers  map[string]struct{}
+	patterns []maskPattern
+}
+
+type maskPattern struct {
+	re   *regexp.Regexp
+	mask func(string) string
+}
+
+var builtinPatterns = map[string][]maskPattern{
+	PatternToken: {
+		{re: regexp.MustCompile(`(?i)\bBearer\s+[A-Za-z0-9._~+/=-]+`), mask: func(string) string { return "Bearer " + Redacted }},
+		{re: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), mask: func(string) string { return Redacted }},
+	},
+	PatternEmail: {
+		{re: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), mask: func(s string) string {
+			at := strings.IndexByte(s, '@')
+			return s[:1] + Redacted + s[at:]
+		}},
+	},
+	PatternPhone: {
+		{re: regexp.MustCompile(`\b1[3-9]\d{9}\b`), mask: func(s string) string { return s[:3] + "****" + s[7:] }},
+	},
+}
+
+func newRedactor(c RedactConfig) (*redactor, error) {
+	r := &redactor{headers: make(map[string]struct{})}
+	for _, f := range slices.Concat(defaultRedactFields, c.Fields) {
+		if f = normalizeKey(f); f != "" && !slices.Contains(r.fields, f) {
+			r.fields = append(r.fields, f)
+		}
+	}
+	for _, h := range slices.Concat(defaultRedactHeaders, c.Headers) {
+		if h = strings.TrimSpace(h); h != "" {
+			r.headers[http.CanonicalHeaderKey(h)] = struct{}{}
+		}
+	}
+
+	names := c.Patterns
+	if len(names) == 0 {
+		names = []string{PatternToken, PatternEmail, PatternPhone}
+	}
+	for _, name := range names {
+		ps, ok := builtinPatterns[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("log.redact.patterns: 不支持的脱敏模式 %q（可选 token / email / phone）", name)
+		}
+		r.patterns = append(r.patterns, ps...)
+	}
+	return r, nil
+}
+
+func normalizeKey(k string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(k)), "-", "_")
+}
+
+// sensitive 字段名是否在脱敏列表中：完全相同或以 _<名称> 结尾
+func (r *redactor) sensitive(key string) bool {
+	k := normalizeKey(key)
+	for _, f := range r.fields {
+		if k == f || strings.HasSuffix(k, "_"+f) {
+			return true
+		}
+	}
+	return false
+}
+
+// mask 对字符串按模式打码
+func (r *redactor) mask(s string) string {
+	for _, p := range r.patterns {
+		s = p.re.ReplaceAllStringFunc(s, p.mask)
+	}
+	return s
+}
+
+func (r *redactor) attr(a slog.Attr) slog.Attr {
+	v := a.Value.Resolve()
+	if a.Key != "" && r.sensitive(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, r.mask(v.String()))
+	case slog.KindGroup:
+		attrs := v.Group()
+		out := make([]slog.Attr, len(attrs))
+		for i, ga := range attrs {
+			out[i] = r.attr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(out...)}
+	case slog.KindAny:
+		return slog.Any(a.Key, r.any(v.Any()))
+	}
+	return slog.Attr{Key: a.Key, Value: v}
+}
+
+// any 脱敏 Any 取值：error 转为打码后的字符串，map / slice（如解码后的 JSON 请求体）逐层按字段名脱敏
+func (r *redactor) any(v any) any {
+	switch t := v.(type) {
+	case error:
+		return r.mask(t.Error())
+	case string:
+		return r.mask(t)
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, item := range t {
+			if r.sensitive(k) {
+				out[k] = Redacted
+				continue
+			}
+			out[k] = r.any(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, item := range t {
+			out[i] = r.any(item)
+		}
+		return out
+	case []string:
+		out := make([]string, len(t))
+		for i, item := range t {
+			out[i] = r.mask(item)
+		}
+		return out
+	}
+	return v
+}
+
+// redactHandler 输出前对日志记录脱敏：按字段名整体替换、按模式对字符串打码（含消息本身）
+type redactHandler struct {
+	next slog.Handler
+	r    *redactor
+}
+
+func newRedactHandler(next slog.Handler, r *redactor) slog.Handler {
+	return redactHandler{next: next, r: r}
+}
+
+func (h redactHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+func (h redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, h.r.mask(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(h.r.attr(a))
+		return true
+	})
+	return h.next.Handle(ctx, out)
+}
+
+func (h redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		out[i] = h.r.attr(a)
+	}
+	return redactHandler{next: h.next.WithAttrs(out), r: h.r}
+}
+
+func (h redactHandler) WithGroup(name string) slog.Handler {
+	return redactHandler{next: h.next.WithGroup(name), r: h.r}
+}
+
+// Headers 将请求 / 响应头转为日志取值，脱敏列表中的头（如 Authorization、Cookie）取值替换为 ***
+func Headers(h http.Header) slog.Value {
+	r := redaction.Load()
+	attrs := make([]slog.Attr, 0, len(h))
+	for _, k := range slices.Sorted(maps.Keys(h)) {
+		v := strings.Join(h[k], ", ")
+		if _, ok := r.headers[http.CanonicalHeaderKey(k)]; ok {
+			v = Redacted
+		}
+		attrs = append(attrs, slog.String(k, v))
+	}
+	return slog.GroupValue(attrs...)
+}
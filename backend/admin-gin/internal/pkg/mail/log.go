@@ -0,0 +1,50 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LogMailer 不真正发送邮件：输出到日志，并可另存为 .eml 文件（可直接用邮件客户端打开），用于本地开发
+type LogMailer struct {
+	from *mail.Address
+	dir  string
+}
+
+// NewLogMailer 构造日志发送器，dir 为空时仅输出日志
+func NewLogMailer(from, dir string) *LogMailer {
+	addr, err := mail.ParseAddress(from)
+	if err != nil {
+		addr = &mail.Address{Address: "no-reply@localhost"}
+	}
+	return &LogMailer{from: addr, dir: dir}
+}
+
+// Send 输出邮件内容
+func (m *LogMailer) Send(ctx context.Context, msg *Message) error {
+	data, err := build(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	attrs := []any{"to", strings.Join(msg.To, ","), "subject", msg.Subject, "text", msg.Text}
+	if m.dir != "" {
+		if err := os.MkdirAll(m.dir, 0o755); err != nil {
+			return err
+		}
+		name := filepath.Join(m.dir, fmt.Sprintf("%s.eml", time.Now().Format("20060102-150405.000000000")))
+		if err := os.WriteFile(name, data, 0o644); err != nil {
+			return err
+		}
+		attrs = append(attrs, "file", name)
+	}
+
+	slog.InfoContext(ctx, "邮件未实际发送（log 驱动）", attrs...)
+	return nil
+}
@@ -0,0 +1,46 @@
+// 邮件发送：Mailer 接口 + SMTP / 日志（本地开发）实现 + 多语言模板
+package mail
+
+import (
+	"context"
+	"fmt"
+)
+
+// 发送驱动
+const (
+	DriverSMTP = "smtp" // 通过 SMTP 服务器发送
+	DriverLog  = "log"  // 仅输出到日志（可另存为 .eml 文件），用于本地开发
+)
+
+// Message 邮件内容，Text 与 HTML 至少提供一个（同时提供时以 multipart/alternative 发送）
+type Message struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer 邮件发送能力，业务模块只依赖该接口
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Config 邮件配置（由 boot 从 configs.Mail 转换）
+type Config struct {
+	Driver string // smtp / log，默认 log
+	From   string // 发件人，如 "Mall Admin <no-reply@example.com>"
+	Dir    string // log 驱动：邮件另存为 .eml 文件的目录，留空仅输出日志
+	SMTP   SMTPConfig
+}
+
+// New 按驱动构造 Mailer
+func New(cfg Config) (Mailer, error) {
+	switch cfg.Driver {
+	case DriverSMTP:
+		return NewSMTPMailer(cfg.From, cfg.SMTP)
+	case DriverLog, "":
+		return NewLogMailer(cfg.From, cfg.Dir), nil
+	default:
+		return nil, fmt.Errorf("mail: 不支持的发送驱动 %q", cfg.Driver)
+	}
+}
@@ -0,0 +1,76 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// DefaultQueueSize 邮件队列默认容量
+const DefaultQueueSize = 100
+
+// queueSendTimeout 单封邮件的发送超时
+const queueSendTimeout = 30 * time.Second
+
+// ErrQueueFull 队列已满，邮件未入队
+var ErrQueueFull = errors.New("mail: 发送队列已满")
+
+// Queue 异步发送邮件：Send 仅入队并立即返回，由 Run 在后台逐封发送
+// Run 须通过 lifecycle.Go 启动：停机时先发送完队列中剩余的邮件再退出，未发送成功的邮件记录日志
+type Queue struct {
+	next Mailer
+	ch   chan queued
+}
+
+type queued struct {
+	ctx context.Context
+	msg *Message
+}
+
+// NewQueue 构造邮件队列，size <= 0 时取 DefaultQueueSize
+func NewQueue(next Mailer, size int) *Queue {
+	if size <= 0 {
+		size = DefaultQueueSize
+	}
+	return &Queue{next: next, ch: make(chan queued, size)}
+}
+
+// Send 邮件入队（不阻塞）；ctx 仅用于携带日志字段（request_id 等），取消不影响发送
+func (q *Queue) Send(ctx context.Context, msg *Message) error {
+	select {
+	case q.ch <- queued{ctx: context.WithoutCancel(ctx), msg: msg}:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Run 逐封发送队列中的邮件，ctx 取消后发送完剩余邮件再返回
+func (q *Queue) Run(ctx context.Context) {
+	for {
+		select {
+		case m := <-q.ch:
+			q.send(m)
+		case <-ctx.Done():
+			for {
+				select {
+				case m := <-q.ch:
+					q.send(m)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (q *Queue) send(m queued) {
+	ctx, cancel := context.WithTimeout(m.ctx, queueSendTimeout)
+	defer cancel()
+
+	if err := q.next.Send(ctx, m.msg); err != nil {
+		slog.ErrorContext(ctx, "发送邮件失败", "to", strings.Join(m.msg.To, ","), "subject", m.msg.Subject, "error", err.Error())
+	}
+}
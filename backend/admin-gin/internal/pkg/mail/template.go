@@ -0,0 +1,87 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// DefaultLang 默认语言：请求的语言没有对应模板时回退
+const DefaultLang = "zh"
+
+// Templates 多语言邮件模板
+// 模板文件命名为 "<name>.<lang>.tmpl"，文件内通过 define 定义三个块：
+// "subject"（主题）、"text"（纯文本正文）、"html"（HTML 正文，按 html/template 转义）
+type Templates struct {
+	text map[string]*texttemplate.Template
+	html map[string]*htmltemplate.Template
+}
+
+// ParseTemplates 解析 fsys 根目录下的全部 *.tmpl 文件
+func ParseTemplates(fsys fs.FS) (*Templates, error) {
+	files, err := fs.Glob(fsys, "*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Templates{
+		text: make(map[string]*texttemplate.Template, len(files)),
+		html: make(map[string]*htmltemplate.Template, len(files)),
+	}
+	for _, file := range files {
+		key := strings.TrimSuffix(path.Base(file), ".tmpl")
+		if t.text[key], err = texttemplate.ParseFS(fsys, file); err != nil {
+			return nil, err
+		}
+		if t.html[key], err = htmltemplate.ParseFS(fsys, file); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// MustParseTemplates 同 ParseTemplates，解析失败时 panic（用于 embed 的内置模板）
+func MustParseTemplates(fsys fs.FS) *Templates {
+	t, err := ParseTemplates(fsys)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Render 渲染模板 name 的 lang 语言版本，缺少该语言时回退到 DefaultLang
+func (t *Templates) Render(name, lang string, data any) (*Message, error) {
+	key := name + "." + lang
+	if _, ok := t.text[key]; !ok {
+		key = name + "." + DefaultLang
+	}
+	tt, ok := t.text[key]
+	if !ok {
+		return nil, fmt.Errorf("mail: 模板 %s 不存在", name)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := tt.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, err
+	}
+	if tt.Lookup("text") != nil {
+		if err := tt.ExecuteTemplate(&text, "text", data); err != nil {
+			return nil, err
+		}
+	}
+	if ht := t.html[key]; ht.Lookup("html") != nil {
+		if err := ht.ExecuteTemplate(&html, "html", data); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()),
+		HTML:    strings.TrimSpace(html.String()),
+	}, nil
+}
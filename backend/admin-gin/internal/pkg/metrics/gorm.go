@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gorm.io/gorm"
+)
+
+var (
+	dbDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "数据库语句执行耗时（秒）",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	dbErrors = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Name: "db_query_errors_total",
+		Help: "数据库语句执行失败次数（不含记录不存在）",
+	}, []string{"operation", "table"})
+)
+
+// gormStartKey 语句开始时间在 gorm.Statement 中的存储键
+const gormStartKey = "metrics:start"
+
+// InstrumentDB 为 gorm 注册语句耗时 / 错误回调，并采集 sql.DB 连接池状态（go_sql_* 指标）
+func InstrumentDB(db *gorm.DB, name string) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	if err := db.Use(gormPlugin{}); err != nil {
+		return err
+	}
+	return Registry.Register(collectors.NewDBStatsCollector(sqlDB, name))
+}
+
+// gormPlugin 在各类语句的 gorm 默认回调前后记录耗时
+type gormPlugin struct{}
+
+func (gormPlugin) Name() string {
+	return "metrics"
+}
+
+func (gormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("metrics:before_create", beforeStatement),
+		cb.Create().After("gorm:create").Register("metrics:after_create", afterStatement("create")),
+		cb.Query().Before("gorm:query").Register("metrics:before_query", beforeStatement),
+		cb.Query().After("gorm:query").Register("metrics:after_query", afterStatement("query")),
+		cb.Update().Before("gorm:update").Register("metrics:before_update", beforeStatement),
+		cb.Update().After("gorm:update").Register("metrics:after_update", afterStatement("update")),
+		cb.Delete().Before("gorm:delete").Register("metrics:before_delete", beforeStatement),
+		cb.Delete().After("gorm:delete").Register("metrics:after_delete", afterStatement("delete")),
+		cb.Row().Before("gorm:row").Register("metrics:before_row", beforeStatement),
+		cb.Row().After("gorm:row").Register("metrics:after_row", afterStatement("row")),
+		cb.Raw().Before("gorm:raw").Register("metrics:before_raw", beforeStatement),
+		cb.Raw().After("gorm:raw").Register("metrics:after_raw", afterStatement("raw")),
+	)
+}
+
+func beforeStatement(db *gorm.DB) {
+	db.InstanceSet(gormStartKey, time.Now())
+}
+
+func afterStatement(op string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(gormStartKey)
+		if !ok {
+			return
+		}
+		start, _ := v.(time.Time)
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown" // 原生 SQL 无法确定表名
+		}
+		dbDuration.WithLabelValues(op, table).Observe(time.Since(start).Seconds())
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			dbErrors.WithLabelValues(op, table).Inc()
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry 本服务的指标注册表（不使用 prometheus 全局注册表，避免第三方库隐式注册的指标混入）
+// 业务模块通过 promauto.With(metrics.Registry) 定义自己的指标
+var Registry = prometheus.NewRegistry()
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// unmatchedRoute 未匹配到路由的请求（404）统一使用该标签，避免任意路径导致标签基数膨胀
+const unmatchedRoute = "unmatched"
+
+var (
+	httpRequests = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Name: "http_server_requests_total",
+		Help: "HTTP 请求总数",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_server_request_duration_seconds",
+		Help:    "HTTP 请求处理耗时（秒）",
+		Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"method", "route", "status"})
+
+	httpInFlight = promauto.With(Registry).NewGauge(prometheus.GaugeOpts{
+		Name: "http_server_requests_in_flight",
+		Help: "正在处理的 HTTP 请求数",
+	})
+)
+
+// ObserveHTTP 记录一次 HTTP 请求；route 为 gin 路由模板（c.FullPath()），为空表示未匹配到路由
+func ObserveHTTP(method, route string, status int, d time.Duration) {
+	if route == "" {
+		route = unmatchedRoute
+	}
+	code := strconv.Itoa(status)
+	httpRequests.WithLabelValues(method, route, code).Inc()
+	httpDuration.WithLabelValues(method, route, code).Observe(d.Seconds())
+}
+
+// InFlight 正在处理的请求数 +1，返回的函数在请求结束时调用
+func InFlight() func() {
+	httpInFlight.Inc()
+	return httpInFlight.Dec
+}
+
+// Handler 暴露 Prometheus 指标；token 非空时要求请求头 Authorization: Bearer <token>
+func Handler(token string) gin.HandlerFunc {
+	h := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+	want := []byte("Bearer " + token)
+	return func(c *gin.Context) {
+		if token != "" && subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), want) != 1 {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(c.Writer, c.Request)
+	}
+}
@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	redisDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "redis_command_duration_seconds",
+		Help:    "redis 命令执行耗时（秒），pipeline 整体记录为 command=pipeline",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5},
+	}, []string{"command"})
+
+	redisErrors = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Name: "redis_command_errors_total",
+		Help: "redis 命令执行失败次数（不含 key 不存在）",
+	}, []string{"command"})
+)
+
+// InstrumentRedis 为 redis 客户端注册命令耗时 / 错误 hook，并采集连接池状态（redis_pool_* 指标）
+func InstrumentRedis(rdb *redis.Client) error {
+	rdb.AddHook(redisHook{})
+	return Registry.Register(&redisPoolCollector{rdb: rdb})
+}
+
+// redisHook 实现 redis.Hook
+type redisHook struct{}
+
+func (redisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		observeRedis(strings.ToLower(cmd.Name()), time.Since(start), err)
+		return err
+	}
+}
+
+func (redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		observeRedis("pipeline", time.Since(start), err)
+		return err
+	}
+}
+
+func observeRedis(command string, d time.Duration, err error) {
+	redisDuration.WithLabelValues(command).Observe(d.Seconds())
+	if err != nil && !errors.Is(err, redis.Nil) {
+		redisErrors.WithLabelValues(command).Inc()
+	}
+}
+
+var (
+	redisPoolHits     = prometheus.NewDesc("redis_pool_hits_total", "从连接池获取到空闲连接的次数", nil, nil)
+	redisPoolMisses   = prometheus.NewDesc("redis_pool_misses_total", "连接池无空闲连接、需新建连接的次数", nil, nil)
+	redisPoolTimeouts = prometheus.NewDesc("redis_pool_timeouts_total", "等待连接池连接超时的次数", nil, nil)
+	redisPoolTotal    = prometheus.NewDesc("redis_pool_connections", "连接池当前连接数", nil, nil)
+	redisPoolIdle     = prometheus.NewDesc("redis_pool_idle_connections", "连接池当前空闲连接数", nil, nil)
+	redisPoolStale    = prometheus.NewDesc("redis_pool_stale_connections_total", "因过期被移出连接池的连接数", nil, nil)
+)
+
+// redisPoolCollector 采集时读取 redis.Client.PoolStats
+type redisPoolCollector struct {
+	rdb *redis.Client
+}
+
+func (c *redisPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- redisPoolHits
+	ch <- redisPoolMisses
+	ch <- redisPoolTimeouts
+	ch <- redisPoolTotal
+	ch <- redisPoolIdle
+	ch <- redisPoolStale
+}
+
+func (c *redisPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.rdb.PoolStats()
+	ch <- prometheus.MustNewConstMetric(redisPoolHits, prometheus.CounterValue, float64(s.Hits))
+	ch <- prometheus.MustNewConstMetric(redisPoolMisses, prometheus.CounterValue, float64(s.Misses))
+	ch <- prometheus.MustNewConstMetric(redisPoolTimeouts, prometheus.CounterValue, float64(s.Timeouts))
+	ch <- prometheus.MustNewConstMetric(redisPoolTotal, prometheus.GaugeValue, float64(s.TotalConns))
+	ch <- prometheus.MustNewConstMetric(redisPoolIdle, prometheus.GaugeValue, float64(s.IdleConns))
+	ch <- prometheus.MustNewConstMetric(redisPoolStale, prometheus.CounterValue, float64(s.StaleConns))
+}
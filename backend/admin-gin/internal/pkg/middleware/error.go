@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"mall-api/internal/pkg/errs"
+	pkghttp "mall-api/internal/pkg/http"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Error 统一错误响应：handler 通过 c.Error(err) 返回错误，由此处按错误类别经 pkghttp.Fail 输出
+//   - *errs.Error：返回类别对应的状态码与错误信息，校验错误的字段明细放在 data 中
+//   - gorm.ErrRecordNotFound：404，不返回原始信息
+//   - 其他错误：记录日志后返回 500，不向客户端暴露原始错误（SQL、redis、网络地址等）
+func Error() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last().Err
+
+		e, ok := errs.As(err)
+		switch {
+		case ok && e.Kind != errs.KindInternal:
+			var data any
+			if len(e.Fields) > 0 {
+				data = e.Fields
+			}
+			pkghttp.FailWithData(c, e.Kind.Status(), e.Message, data)
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			pkghttp.Fail(c, http.StatusNotFound)
+		default:
+			slog.ErrorContext(c.Request.Context(), "请求处理失败", "error", err)
+			pkghttp.Fail(c, http.StatusInternalServerError)
+		}
+	}
+}
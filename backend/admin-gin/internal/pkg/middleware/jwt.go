@@ -1,22 +1,69 @@
 package middleware
 
 import (
+	"context"
+	"log/slog"
 	pkghttp "mall-api/internal/pkg/http"
 	"mall-api/internal/pkg/jwt"
+	"mall-api/internal/pkg/logger"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-var jt *jwt.JWT
+var (
+	jt      *jwt.JWT
+	revoker TokenRevoker
+	status  AccountStatus
+)
+
+// TokenRevoker 令牌吊销校验（由 iam/auth 模块实现，经 boot 注入）
+type TokenRevoker interface {
+	IsRevoked(ctx context.Context, claims *jwt.Claims) (bool, error)
+}
+
+// AccountStatus 账号状态校验（由 iam/auth 模块实现，经 boot 注入）
+type AccountStatus interface {
+	// AccountState 返回账号是否可用，以及是否须先修改密码
+	AccountState(ctx context.Context, uid string) (available, mustChangePassword bool, err error)
+}
+
+// JWTOption JWT 中间件选项
+type JWTOption func(*jwtOptions)
+
+type jwtOptions struct {
+	allowMustChangePassword bool
+}
+
+// AllowMustChangePassword 允许 "须修改密码" 的账号访问（仅用于修改密码、注销等接口）
+func AllowMustChangePassword() JWTOption {
+	return func(o *jwtOptions) {
+		o.allowMustChangePassword = true
+	}
+}
 
 // InitJWT 注入 JWT 引擎供中间件使用
 func InitJWT(svc *jwt.JWT) {
 	jt = svc
 }
 
-func JWT() gin.HandlerFunc {
+// InitRevocation 注入令牌吊销校验；未注入时跳过吊销检查
+func InitRevocation(r TokenRevoker) {
+	revoker = r
+}
+
+// InitAccountStatus 注入账号状态校验；未注入时跳过状态检查
+func InitAccountStatus(s AccountStatus) {
+	status = s
+}
+
+func JWT(opts ...JWTOption) gin.HandlerFunc {
+	var o jwtOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	return func(c *gin.Context) {
 		tokenHeader := c.GetHeader("Authorization")
 
@@ -46,9 +93,41 @@ func JWT() gin.HandlerFunc {
 			return
 		}
 
-		// 3. 存储结果并放行
-		// 存储到上下文供后续 Controller 使用：uid := c.GetUint("uid")
+		// 3. 吊销名单校验：注销、会话下线、用户禁用/删除后立即失效
+		if revoker != nil {
+			revoked, err := revoker.IsRevoked(c.Request.Context(), claims)
+			if err != nil {
+				pkghttp.Fail(c, http.StatusInternalServerError)
+				return
+			}
+			if revoked {
+				pkghttp.Fail(c, http.StatusUnauthorized, "令牌无效或已失效")
+				return
+			}
+		}
+
+		// 4. 账号状态校验：禁用 / 删除后下一次请求即被拒绝；须修改密码的账号只能访问放行的接口
+		if status != nil {
+			available, mustChangePassword, err := status.AccountState(c.Request.Context(), claims.UID)
+			if err != nil {
+				pkghttp.Fail(c, http.StatusInternalServerError)
+				return
+			}
+			if !available {
+				pkghttp.Fail(c, http.StatusUnauthorized, "账号已被禁用或删除")
+				return
+			}
+			if mustChangePassword && !o.allowMustChangePassword {
+				pkghttp.Fail(c, http.StatusForbidden, "请先修改密码")
+				return
+			}
+		}
+
+		// 5. 存储结果并放行
+		// 存储到上下文供后续 Controller 使用：uid := c.GetString("uid")，sid 为当前会话标识
 		c.Set("uid", claims.UID)
+		c.Set("sid", claims.SID)
+		c.Request = c.Request.WithContext(logger.With(c.Request.Context(), slog.String("uid", claims.UID))) // 之后的日志附带 uid
 		c.Next()
 	}
 }
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"log/slog"
+	pkghttp "mall-api/internal/pkg/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,17 +15,21 @@ func Log() gin.HandlerFunc {
 		c.Next()
 
 		latency := time.Since(start)
-		status := c.Writer.Status()
+		status := pkghttp.Status(c) // 业务状态码：失败响应的 HTTP 状态为 200
 
+		// route、request_id、uid 经请求 ctx 由 logger 自动附带
 		attrs := []slog.Attr{
 			slog.String("method", c.Request.Method),
-			slog.String("path", c.FullPath()),
 			slog.Int("status", status),
 			slog.Duration("latency", latency),
 			slog.String("client_ip", c.ClientIP()),
 			slog.String("user_agent", c.Request.UserAgent()),
 		}
 
+		if c.FullPath() == "" {
+			attrs = append(attrs, slog.String("path", c.Request.URL.Path)) // 未匹配到路由
+		}
+
 		if len(c.Errors) > 0 {
 			attrs = append(attrs,
 				slog.String("errors", c.Errors.String()),
@@ -37,13 +42,15 @@ func Log() gin.HandlerFunc {
 			args = append(args, a)
 		}
 
+		// 传入请求 ctx：日志附带 trace_id / span_id
+		ctx := c.Request.Context()
 		switch {
 		case status >= 500:
-			slog.Error("http request error", args...)
+			slog.ErrorContext(ctx, "http request error", args...)
 		case status >= 400:
-			slog.Warn("http request warning", args...)
+			slog.WarnContext(ctx, "http request warning", args...)
 		default:
-			slog.Info("http request", args...)
+			slog.InfoContext(ctx, "http request", args...)
 		}
 	}
 }
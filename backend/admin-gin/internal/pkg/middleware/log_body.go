@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mall-api/internal/pkg/logger"
+	"mime"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultLogBodyMaxSize 请求 / 响应体记录上限（字节）
+const DefaultLogBodyMaxSize = 4096
+
+// LogBody 记录请求 / 响应的头与体（排查问题时按需开启）
+// JSON 与表单按字段解析后输出，经 logger 脱敏（password、token 等字段替换为 ***）；
+// 超过 maxSize 的内容无法完整解析，不记录内容，避免截断后的片段绕过按字段脱敏
+func LogBody(maxSize int) gin.HandlerFunc {
+	if maxSize <= 0 {
+		maxSize = DefaultLogBodyMaxSize
+	}
+	return func(c *gin.Context) {
+		var reqBody []byte
+		var reqTruncated bool
+		if c.Request.Body != nil && c.Request.Body != http.NoBody {
+			buf, err := io.ReadAll(io.LimitReader(c.Request.Body, int64(maxSize)+1))
+			if err == nil {
+				reqBody, reqTruncated = buf, len(buf) > maxSize
+			}
+			// 已读取的部分放回请求体，后续 handler 照常读取完整内容
+			c.Request.Body = readCloser{io.MultiReader(bytes.NewReader(buf), c.Request.Body), c.Request.Body}
+		}
+
+		bw := &bodyWriter{ResponseWriter: c.Writer, limit: maxSize}
+		c.Writer = bw
+
+		c.Next()
+
+		slog.InfoContext(c.Request.Context(), "http body",
+			slog.String("method", c.Request.Method),
+			slog.Attr{Key: "request_headers", Value: logger.Headers(c.Request.Header)},
+			slog.Any("request_body", bodyValue(reqBody, reqTruncated, c.ContentType())),
+			slog.Attr{Key: "response_headers", Value: logger.Headers(c.Writer.Header())},
+			slog.Any("response_body", bodyValue(bw.buf.Bytes(), bw.truncated, contentType(c.Writer.Header().Get("Content-Type")))),
+		)
+	}
+}
+
+// bodyValue 将请求 / 响应体转为日志取值：JSON 解码为 map / slice，表单解码为 map，其他类型只记录大小
+func bodyValue(body []byte, truncated bool, ct string) any {
+	switch {
+	case len(body) == 0:
+		return nil
+	case truncated:
+		return fmt.Sprintf("（超过记录上限，未记录，content-type: %s）", ct)
+	}
+	switch ct {
+	case gin.MIMEJSON:
+		var v any
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			return fmt.Sprintf("（无效的 JSON，%d 字节）", len(body))
+		}
+		return v
+	case gin.MIMEPOSTForm:
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return fmt.Sprintf("（无效的表单，%d 字节）", len(body))
+		}
+		m := make(map[string]any, len(values))
+		for k, vs := range values {
+			if len(vs) == 1 {
+				m[k] = vs[0]
+			} else {
+				m[k] = vs
+			}
+		}
+		return m
+	}
+	return fmt.Sprintf("（%d 字节，content-type: %s）", len(body), ct)
+}
+
+func contentType(header string) string {
+	ct, _, _ := mime.ParseMediaType(header)
+	return ct
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// bodyWriter 在写出响应的同时保留前 limit+1 个字节
+type bodyWriter struct {
+	gin.ResponseWriter
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (w *bodyWriter) Write(b []byte) (int, error) {
+	w.capture(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *bodyWriter) capture(b []byte) {
+	if w.truncated {
+		return
+	}
+	if room := w.limit - w.buf.Len(); len(b) > room {
+		w.buf.Write(b[:room])
+		w.truncated = true
+		return
+	}
+	w.buf.Write(b)
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	pkghttp "mall-api/internal/pkg/http"
+	"mall-api/internal/pkg/metrics"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics 记录请求数、耗时与并发数（按路由模板 c.FullPath()、方法、状态码统计）
+// 状态码取响应体中的业务状态码（pkghttp.Status），失败响应的 HTTP 状态虽为 200 仍按 4xx / 5xx 统计
+// 须注册在 gin.Recovery() 之前，使 panic 恢复后返回的 500 同样被统计
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		done := metrics.InFlight()
+		defer done()
+
+		c.Next()
+
+		metrics.ObserveHTTP(c.Request.Method, c.FullPath(), pkghttp.Status(c), time.Since(start))
+	}
+}
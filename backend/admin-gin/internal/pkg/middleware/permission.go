@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	pkghttp "mall-api/internal/pkg/http"
+	"mall-api/internal/pkg/logger"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionChecker 权限校验能力（由 iam/role 模块实现，权限点缓存在 Redis 中）
+type PermissionChecker interface {
+	HasPermission(ctx context.Context, uid string, code string) (bool, error)
+}
+
+var pc PermissionChecker
+
+// log 权限校验日志（组件 permission，级别可通过 log.levels.permission 单独调整）
+var log = logger.Component("permission")
+
+// InitPermission 注入权限校验能力供中间件使用
+func InitPermission(checker PermissionChecker) {
+	pc = checker
+}
+
+// RequirePermission 路由权限点拦截，必须挂在 JWT() 之后（依赖上下文中的 uid）
+// 传入多个权限点时，需要同时拥有全部权限点才放行
+func RequirePermission(codes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uid := c.GetString("uid")
+		if uid == "" {
+			pkghttp.Fail(c, http.StatusUnauthorized, "缺少认证令牌")
+			return
+		}
+
+		for _, code := range codes {
+			ok, err := pc.HasPermission(c.Request.Context(), uid, code)
+			if err != nil {
+				log.ErrorContext(c.Request.Context(), "权限校验失败", "uid", uid, "permission", code, "error", err)
+				pkghttp.Fail(c, http.StatusInternalServerError)
+				return
+			}
+			if !ok {
+				pkghttp.Fail(c, http.StatusForbidden, "无权限访问")
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
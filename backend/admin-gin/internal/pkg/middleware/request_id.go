@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"log/slog"
+	"mall-api/internal/pkg/logger"
+	"mall-api/internal/pkg/uuid"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader 请求 ID 请求头 / 响应头
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen 上游传入的请求 ID 最大长度
+const maxRequestIDLen = 64
+
+// RequestID 沿用上游（网关、调用方）传入的 X-Request-ID，缺失或格式不合法时生成新 ID；
+// 写入响应头与请求 ctx，之后经 ctx 输出的日志自动附带 request_id 与 route，失败响应体返回 request_id
+// 须注册为第一个中间件，使其余中间件的日志同样附带请求 ID
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewUUID()
+		}
+		c.Header(RequestIDHeader, id)
+
+		ctx := logger.WithRequestID(c.Request.Context(), id)
+		if route := c.FullPath(); route != "" {
+			ctx = logger.With(ctx, slog.String("route", route))
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// validRequestID 仅接受字母、数字与 -_.:，避免日志注入与超长 ID
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
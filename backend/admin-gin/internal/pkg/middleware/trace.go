@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"errors"
+	pkghttp "mall-api/internal/pkg/http"
+	"mall-api/internal/pkg/tracing"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Trace 为每个请求创建 server span：从请求头 traceparent 继承上游链路，span 写入 c.Request 的 ctx，
+// 后续经 ctx 传递的 gorm / redis 调用自动成为其子 span；响应头 traceparent 返回本次请求的链路标识
+// 状态码取响应体中的业务状态码（pkghttp.Status），5xx 时 span 标记为 Error 并记录错误
+// 须注册在 gin.Recovery() 之前，使 panic 恢复后返回的 500 同样记录到 span
+func Trace() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		propagator := otel.GetTextMapPropagator()
+		ctx := propagator.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		name := c.Request.Method + " " + route
+		if route == "" {
+			name = c.Request.Method // 未匹配到路由时不使用原始路径，避免 span 名称基数膨胀
+		}
+		ctx, span := tracing.Tracer().Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Request.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(c.Request.URL.Path),
+				semconv.ClientAddress(c.ClientIP()),
+				semconv.UserAgentOriginal(c.Request.UserAgent()),
+			),
+		)
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		propagator.Inject(ctx, propagation.HeaderCarrier(c.Writer.Header()))
+
+		c.Next()
+
+		status := pkghttp.Status(c)
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if len(c.Errors) > 0 {
+			span.SetAttributes(semconv.ErrorMessage(c.Errors.String()))
+		}
+		if status >= http.StatusInternalServerError {
+			err := errors.New(http.StatusText(status))
+			if len(c.Errors) > 0 {
+				err = c.Errors.Last().Err
+			}
+			span.RecordError(err)
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
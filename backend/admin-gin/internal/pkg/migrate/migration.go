@@ -0,0 +1,144 @@
+package migrate
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// noTxDirective 迁移文件首行声明该指令时不在事务中执行（如 CREATE INDEX CONCURRENTLY）
+const noTxDirective = "-- migrate:no-transaction"
+
+// fileRe 迁移文件名：<version>_<name>.up.sql / <version>_<name>.down.sql
+var fileRe = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// nameRe create 时允许的迁移名称
+var nameRe = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// Migration 单个版本的迁移
+type Migration struct {
+	Version int64
+	Name    string
+
+	Up   string
+	Down string // 为空表示不可回滚
+
+	UpNoTx   bool // up 不在事务中执行
+	DownNoTx bool // down 不在事务中执行
+}
+
+// Load 从 fsys 根目录读取迁移文件，按版本升序返回
+// 同一版本必须有 up 文件，down 文件可选；非迁移文件（如 embed.go）被忽略
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		m := fileRe.FindStringSubmatch(e.Name())
+		if m == nil {
+			if strings.HasSuffix(e.Name(), ".sql") {
+				return nil, fmt.Errorf("migrate: 无法识别的迁移文件 %s", e.Name())
+			}
+			continue
+		}
+
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migrate: 迁移文件 %s 的版本号无效", e.Name())
+		}
+		data, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, err
+		}
+		sql := string(data)
+
+		mg, ok := byVersion[version]
+		if !ok {
+			mg = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mg
+		} else if mg.Name != m[2] {
+			return nil, fmt.Errorf("migrate: 版本 %d 存在多个名称（%s / %s）", version, mg.Name, m[2])
+		}
+
+		switch m[3] {
+		case "up":
+			mg.Up, mg.UpNoTx = sql, noTx(sql)
+		case "down":
+			mg.Down, mg.DownNoTx = sql, noTx(sql)
+		}
+	}
+
+	list := make([]Migration, 0, len(byVersion))
+	for _, mg := range byVersion {
+		if strings.TrimSpace(mg.Up) == "" {
+			return nil, fmt.Errorf("migrate: 版本 %d 缺少 up 迁移", mg.Version)
+		}
+		list = append(list, *mg)
+	}
+	slices.SortFunc(list, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return list, nil
+}
+
+// noTx 首个非空行是否为 noTxDirective
+func noTx(sql string) bool {
+	for line := range strings.Lines(sql) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		return line == noTxDirective
+	}
+	return false
+}
+
+// upPlaceholder 新建 up 文件的占位内容：非空，编写 SQL 前执行 status / create 等命令时 Load 不会报错
+const upPlaceholder = "-- %s：替换本行，编写 up 迁移 SQL（不在事务中执行时首行须为 " + noTxDirective + "）\n"
+
+// Create 在 dir 目录下新建 up/down 迁移文件，版本号为现有最大版本 + 1，返回创建的文件路径
+// up 文件写入占位注释；down 文件为空（保持为空表示不可回滚）
+func Create(dir, name string) ([]string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !nameRe.MatchString(name) {
+		return nil, errors.New("migrate: 迁移名称只能包含小写字母、数字与下划线")
+	}
+
+	list, err := Load(os.DirFS(dir))
+	if err != nil {
+		return nil, fmt.Errorf("migrate: 读取迁移目录 %s 失败: %w", dir, err)
+	}
+	var version int64 = 1
+	if len(list) > 0 {
+		version = list[len(list)-1].Version + 1
+	}
+
+	base := fmt.Sprintf("%06d_%s", version, name)
+	files := []string{
+		filepath.Join(dir, base+".up.sql"),
+		filepath.Join(dir, base+".down.sql"),
+	}
+	contents := [][]byte{
+		fmt.Appendf(nil, upPlaceholder, base),
+		nil,
+	}
+	for i, f := range files {
+		if err := os.WriteFile(f, contents[i], 0o644); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
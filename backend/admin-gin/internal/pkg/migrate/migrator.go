@@ -0,0 +1,276 @@
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// Table 迁移版本记录表
+const Table = "schema_migrations"
+
+var (
+	ErrDirty          = errors.New("migrate: 存在 dirty 状态的迁移，请人工修复数据库后执行 force")
+	ErrNoDown         = errors.New("migrate: 迁移不可回滚（缺少 down 文件）")
+	ErrUnknownVersion = errors.New("migrate: 未知的迁移版本")
+)
+
+// Status 单个版本的迁移状态
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	Dirty     bool       // 非事务迁移执行失败，数据库可能处于中间状态
+	Missing   bool       // 已执行但迁移文件已不存在
+	AppliedAt *time.Time // 执行时间，未执行为 nil
+}
+
+// Migrator 基于 schema_migrations 表的版本化 SQL 迁移（PostgreSQL）
+// 所有操作在同一连接上持有 advisory lock 执行，多实例同时迁移时串行
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func New(db *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// record schema_migrations 中的一行
+type record struct {
+	name      string
+	dirty     bool
+	appliedAt time.Time
+}
+
+// Up 按版本升序执行全部未执行的迁移，返回执行数量
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	n := 0
+	err := m.locked(ctx, func(conn *sql.Conn, applied map[int64]record) error {
+		if err := checkDirty(applied); err != nil {
+			return err
+		}
+		for _, mg := range m.migrations {
+			if _, ok := applied[mg.Version]; ok {
+				continue
+			}
+			start := time.Now()
+			if err := m.apply(ctx, conn, mg, true); err != nil {
+				return fmt.Errorf("migrate: 执行 %06d_%s 失败: %w", mg.Version, mg.Name, err)
+			}
+			slog.Info("迁移已执行", "version", mg.Version, "name", mg.Name, "cost", time.Since(start))
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+// Down 按版本倒序回滚最近 steps 个已执行的迁移，返回回滚数量
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	n := 0
+	err := m.locked(ctx, func(conn *sql.Conn, applied map[int64]record) error {
+		if err := checkDirty(applied); err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && n < steps; i-- {
+			mg := m.migrations[i]
+			if _, ok := applied[mg.Version]; !ok {
+				continue
+			}
+			if mg.Down == "" {
+				return fmt.Errorf("%w: %06d_%s", ErrNoDown, mg.Version, mg.Name)
+			}
+			start := time.Now()
+			if err := m.apply(ctx, conn, mg, false); err != nil {
+				return fmt.Errorf("migrate: 回滚 %06d_%s 失败: %w", mg.Version, mg.Name, err)
+			}
+			slog.Info("迁移已回滚", "version", mg.Version, "name", mg.Name, "cost", time.Since(start))
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+// Status 返回全部迁移的执行状态（按版本升序），包含已执行但文件已删除的版本
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var list []Status
+	err := m.locked(ctx, func(_ *sql.Conn, applied map[int64]record) error {
+		known := make(map[int64]bool, len(m.migrations))
+		for _, mg := range m.migrations {
+			known[mg.Version] = true
+			st := Status{Version: mg.Version, Name: mg.Name}
+			if r, ok := applied[mg.Version]; ok {
+				st.Applied, st.Dirty, st.AppliedAt = true, r.dirty, &r.appliedAt
+			}
+			list = append(list, st)
+		}
+		for v, r := range applied {
+			if !known[v] {
+				list = append(list, Status{Version: v, Name: r.name, Applied: true, Dirty: r.dirty, Missing: true, AppliedAt: &r.appliedAt})
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(list, func(a, b Status) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return list, err
+}
+
+// Force 不执行任何 SQL，直接将版本记录设置为 version：不大于 version 的迁移视为已执行，其余视为未执行，并清除 dirty 标记
+// 用于非事务迁移失败、人工修复数据库之后；version 为 0 表示清空全部记录
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version != 0 && !slices.ContainsFunc(m.migrations, func(mg Migration) bool { return mg.Version == version }) {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	return m.locked(ctx, func(conn *sql.Conn, applied map[int64]record) error {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+Table+` WHERE version > $1`, version); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE `+Table+` SET dirty = false WHERE dirty`); err != nil {
+			return err
+		}
+		for _, mg := range m.migrations {
+			if _, ok := applied[mg.Version]; ok || mg.Version > version {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, `INSERT INTO `+Table+` (version, name) VALUES ($1, $2)`, mg.Version, mg.Name); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	})
+}
+
+// locked 获取 advisory lock 后读取版本记录并执行 fn，结束后释放锁
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn, applied map[int64]record) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// advisory lock 属于会话级锁，必须在同一连接上加锁与解锁
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock(hashtext($1))`, Table); err != nil {
+		return fmt.Errorf("migrate: 获取迁移锁失败: %w", err)
+	}
+	defer func() {
+		if _, unlockErr := conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock(hashtext($1))`, Table); unlockErr != nil && err == nil {
+			err = fmt.Errorf("migrate: 释放迁移锁失败: %w", unlockErr)
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+Table+` (
+		version    bigint PRIMARY KEY,
+		name       varchar(255) NOT NULL,
+		dirty      boolean NOT NULL DEFAULT false,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`); err != nil {
+		return err
+	}
+
+	applied, err := loadApplied(ctx, conn)
+	if err != nil {
+		return err
+	}
+	return fn(conn, applied)
+}
+
+func loadApplied(ctx context.Context, conn *sql.Conn) (map[int64]record, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, name, dirty, applied_at FROM `+Table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]record)
+	for rows.Next() {
+		var (
+			v int64
+			r record
+		)
+		if err := rows.Scan(&v, &r.name, &r.dirty, &r.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[v] = r
+	}
+	return applied, rows.Err()
+}
+
+func checkDirty(applied map[int64]record) error {
+	for v, r := range applied {
+		if r.dirty {
+			return fmt.Errorf("%w（版本 %06d_%s）", ErrDirty, v, r.name)
+		}
+	}
+	return nil
+}
+
+// apply 执行单个迁移的 up（up=true）或 down，并同步版本记录：SQL 与版本记录在同一事务中提交
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, mg Migration, up bool) error {
+	script, notx := mg.Down, mg.DownNoTx
+	if up {
+		script, notx = mg.Up, mg.UpNoTx
+	}
+
+	if notx {
+		return m.applyNoTx(ctx, conn, mg, script, up)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if up {
+		_, err = tx.ExecContext(ctx, `INSERT INTO `+Table+` (version, name) VALUES ($1, $2)`, mg.Version, mg.Name)
+	} else {
+		_, err = tx.ExecContext(ctx, `DELETE FROM `+Table+` WHERE version = $1`, mg.Version)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// applyNoTx 非事务迁移：执行前先标记 dirty，成功后再清除，失败时保留 dirty 等待人工处理
+func (m *Migrator) applyNoTx(ctx context.Context, conn *sql.Conn, mg Migration, script string, up bool) error {
+	var err error
+	if up {
+		_, err = conn.ExecContext(ctx, `INSERT INTO `+Table+` (version, name, dirty) VALUES ($1, $2, true)`, mg.Version, mg.Name)
+	} else {
+		_, err = conn.ExecContext(ctx, `UPDATE `+Table+` SET dirty = true WHERE version = $1`, mg.Version)
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := conn.ExecContext(ctx, script); err != nil {
+		return err
+	}
+
+	if up {
+		_, err = conn.ExecContext(ctx, `UPDATE `+Table+` SET dirty = false, applied_at = now() WHERE version = $1`, mg.Version)
+	} else {
+		_, err = conn.ExecContext(ctx, `DELETE FROM `+Table+` WHERE version = $1`, mg.Version)
+	}
+	return err
+}
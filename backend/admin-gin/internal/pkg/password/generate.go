@@ -0,0 +1,74 @@
+package password
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+)
+
+// 生成临时密码使用的字符集：去除易混淆的 0/O、1/l/I
+const (
+	upperChars  = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	lowerChars  = "abcdefghijkmnopqrstuvwxyz"
+	digitChars  = "23456789"
+	symbolChars = "!@#$%^&*-_=+?"
+)
+
+// defaultGenerateLength 临时密码默认长度（策略最小长度更大时取最小长度）
+const defaultGenerateLength = 16
+
+// Generate 生成满足策略的随机临时密码（每类字符至少一个），username 用于校验生成结果不包含用户名
+func (p Policy) Generate(username string) (string, error) {
+	n := min(max(defaultGenerateLength, p.minLength()), p.maxLength())
+	classes := []string{upperChars, lowerChars, digitChars}
+	if p.RequireSymbol {
+		classes = append(classes, symbolChars)
+	}
+	if n < len(classes) {
+		return "", errors.New("password: 最大长度过短，无法生成临时密码")
+	}
+	all := ""
+	for _, c := range classes {
+		all += c
+	}
+
+	for range 10 {
+		buf := make([]byte, 0, n)
+		for _, c := range classes {
+			ch, err := randomChar(c)
+			if err != nil {
+				return "", err
+			}
+			buf = append(buf, ch)
+		}
+		for len(buf) < n {
+			ch, err := randomChar(all)
+			if err != nil {
+				return "", err
+			}
+			buf = append(buf, ch)
+		}
+
+		// 打乱顺序：必选字符不固定出现在开头
+		for i := len(buf) - 1; i > 0; i-- {
+			j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+			if err != nil {
+				return "", err
+			}
+			buf[i], buf[j.Int64()] = buf[j.Int64()], buf[i]
+		}
+
+		if pw := string(buf); p.Validate(pw, username) == nil {
+			return pw, nil
+		}
+	}
+	return "", errors.New("password: 生成临时密码失败")
+}
+
+func randomChar(chars string) (byte, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+	if err != nil {
+		return 0, err
+	}
+	return chars[i.Int64()], nil
+}
@@ -0,0 +1,141 @@
+// 密码策略：长度、字符类型、常见弱密码、历史密码复用校验
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"mall-api/internal/pkg/errs"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// bcryptMaxLength bcrypt 只处理前 72 字节，超出部分会被 GenerateFromPassword 拒绝
+const bcryptMaxLength = 72
+
+const (
+	defaultMinLength = 8
+	defaultHistory   = 5
+)
+
+//go:embed common.txt
+var commonList string
+
+// common 常见弱密码集合（小写），首次使用时加载
+var common = sync.OnceValue(func() map[string]struct{} {
+	set := make(map[string]struct{})
+	sc := bufio.NewScanner(strings.NewReader(commonList))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		set[strings.ToLower(line)] = struct{}{}
+	}
+	return set
+})
+
+// Policy 密码策略（由 boot 从 configs.Auth.Password 转换后注入 auth / user 模块）
+type Policy struct {
+	MinLength     int  // 最小长度，0 时取 8
+	MaxLength     int  // 最大长度（字节），0 或超过 72 时取 72（bcrypt 上限）
+	RequireUpper  bool // 必须包含大写字母
+	RequireLower  bool // 必须包含小写字母
+	RequireDigit  bool // 必须包含数字
+	RequireSymbol bool // 必须包含特殊字符
+	History       int  // 修改密码时不可与最近 N 次使用过的密码（含当前密码）相同，0 时取 5，负数表示不限制
+}
+
+// policyError 密码不满足策略：业务校验错误（422），信息可直接返回给前端
+func policyError(msg string) error {
+	return errs.Validation(msg)
+}
+
+// Validate 校验明文密码是否满足策略，username 用于拒绝包含用户名的密码
+func (p Policy) Validate(password, username string) error {
+	if n := len([]rune(password)); n < p.minLength() {
+		return policyError(fmt.Sprintf("密码长度不能少于 %d 位", p.minLength()))
+	}
+	if len(password) > p.maxLength() {
+		return policyError(fmt.Sprintf("密码长度不能超过 %d 个字节", p.maxLength()))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsSpace(r):
+		default:
+			symbol = true
+		}
+	}
+	var missing []string
+	if p.RequireUpper && !upper {
+		missing = append(missing, "大写字母")
+	}
+	if p.RequireLower && !lower {
+		missing = append(missing, "小写字母")
+	}
+	if p.RequireDigit && !digit {
+		missing = append(missing, "数字")
+	}
+	if p.RequireSymbol && !symbol {
+		missing = append(missing, "特殊字符")
+	}
+	if len(missing) > 0 {
+		return policyError("密码必须包含" + strings.Join(missing, "、"))
+	}
+
+	lowered := strings.ToLower(password)
+	if _, ok := common()[lowered]; ok {
+		return policyError("密码过于常见，请更换")
+	}
+	if name := strings.ToLower(strings.TrimSpace(username)); len(name) >= 3 && strings.Contains(lowered, name) {
+		return policyError("密码不能包含用户名")
+	}
+	return nil
+}
+
+// HistorySize 需要校验的历史密码数量（含当前密码），0 表示不校验
+func (p Policy) HistorySize() int {
+	switch {
+	case p.History < 0:
+		return 0
+	case p.History == 0:
+		return defaultHistory
+	default:
+		return p.History
+	}
+}
+
+// Reused 判断明文密码是否与任一历史密码 hash 相同，相同时返回策略错误
+func (p Policy) Reused(password string, hashes []string) error {
+	for _, h := range hashes {
+		if bcrypt.CompareHashAndPassword([]byte(h), []byte(password)) == nil {
+			return policyError(fmt.Sprintf("新密码不能与最近 %d 次使用过的密码相同", p.HistorySize()))
+		}
+	}
+	return nil
+}
+
+func (p Policy) minLength() int {
+	if p.MinLength > 0 {
+		return p.MinLength
+	}
+	return defaultMinLength
+}
+
+func (p Policy) maxLength() int {
+	if p.MaxLength > 0 && p.MaxLength < bcryptMaxLength {
+		return p.MaxLength
+	}
+	return bcryptMaxLength
+}
@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// gormSpanKey span 在 gorm.Statement 中的存储键
+const gormSpanKey = "tracing:span"
+
+// InstrumentDB 为 gorm 注册回调：每条语句一个 span，父 span 取自 db.WithContext(ctx) 传入的请求 ctx
+// span 记录带占位符的 SQL（不含参数值），避免敏感数据进入链路系统
+func InstrumentDB(db *gorm.DB) error {
+	return db.Use(gormPlugin{})
+}
+
+type gormPlugin struct{}
+
+func (gormPlugin) Name() string {
+	return "tracing"
+}
+
+func (gormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("tracing:before_create", beforeStatement("create")),
+		cb.Create().After("gorm:create").Register("tracing:after_create", afterStatement),
+		cb.Query().Before("gorm:query").Register("tracing:before_query", beforeStatement("query")),
+		cb.Query().After("gorm:query").Register("tracing:after_query", afterStatement),
+		cb.Update().Before("gorm:update").Register("tracing:before_update", beforeStatement("update")),
+		cb.Update().After("gorm:update").Register("tracing:after_update", afterStatement),
+		cb.Delete().Before("gorm:delete").Register("tracing:before_delete", beforeStatement("delete")),
+		cb.Delete().After("gorm:delete").Register("tracing:after_delete", afterStatement),
+		cb.Row().Before("gorm:row").Register("tracing:before_row", beforeStatement("row")),
+		cb.Row().After("gorm:row").Register("tracing:after_row", afterStatement),
+		cb.Raw().Before("gorm:raw").Register("tracing:before_raw", beforeStatement("raw")),
+		cb.Raw().After("gorm:raw").Register("tracing:after_raw", afterStatement),
+	)
+}
+
+func beforeStatement(op string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+			return // 不在请求链路中（如启动阶段、后台任务未传 span）时不单独产生根 span
+		}
+		name := "gorm." + op
+		if db.Statement.Table != "" {
+			name += " " + db.Statement.Table
+		}
+		_, span := Tracer().Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemNamePostgreSQL, semconv.DBOperationName(op)),
+		)
+		db.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func afterStatement(db *gorm.DB) {
+	v, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	if db.Statement.Table != "" {
+		span.SetAttributes(semconv.DBCollectionName(db.Statement.Table))
+	}
+	span.SetAttributes(
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		semconv.DBResponseReturnedRows(int(db.Statement.RowsAffected)),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
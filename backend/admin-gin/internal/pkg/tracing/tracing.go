@@ -0,0 +1,88 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 导出方式
+const (
+	ExporterOTLP   = "otlp"   // OTLP/HTTP 导出到 collector / Jaeger / Tempo 等
+	ExporterStdout = "stdout" // 输出到标准输出（本地调试）
+	ExporterNone   = "none"   // 不导出：仍生成 trace id / span id 供日志关联与向下游传递（测试、离线环境）
+)
+
+// instrumentation 本服务自带埋点的 instrumentation scope 名称
+const instrumentation = "mall-api"
+
+type Config struct {
+	ServiceName    string
+	ServiceVersion string
+
+	Exporter    string  // otlp / stdout / none，为空时取 none
+	Endpoint    string  // OTLP/HTTP 地址（如 http://localhost:4318），为空时读取 OTEL_EXPORTER_OTLP_ENDPOINT 等标准环境变量
+	SampleRatio float64 // 根 span 采样率 (0,1]，<=0 或 >1 时取 1；请求携带 traceparent 时遵循上游的采样决定
+}
+
+// New 初始化全局 TracerProvider 与 W3C traceparent / baggage 传播器
+// 返回的 shutdown 在停机时调用，导出缓冲区中剩余的 span
+func New(ctx context.Context, c Config) (shutdown func(context.Context) error, err error) {
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(c.ServiceName),
+		semconv.ServiceVersion(c.ServiceVersion),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	ratio := c.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	}
+
+	switch c.Exporter {
+	case ExporterOTLP:
+		var eo []otlptracehttp.Option
+		if c.Endpoint != "" {
+			eo = append(eo, otlptracehttp.WithEndpointURL(c.Endpoint))
+		}
+		exp, err := otlptracehttp.New(ctx, eo...)
+		if err != nil {
+			return nil, fmt.Errorf("初始化 OTLP 导出器失败: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exp))
+	case ExporterStdout:
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, sdktrace.WithBatcher(exp))
+	case ExporterNone, "":
+	default:
+		return nil, fmt.Errorf("不支持的链路追踪导出方式: %s", c.Exporter)
+	}
+
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return tp.Shutdown, nil
+}
+
+// Tracer 本服务埋点使用的 tracer，每次从全局 TracerProvider 获取，未调用 New 时为 no-op
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentation)
+}
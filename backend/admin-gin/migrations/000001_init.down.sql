@@ -0,0 +1,9 @@
+-- 回滚基线：删除全部业务表（数据不可恢复）
+DROP TABLE IF EXISTS "menu";
+DROP TABLE IF EXISTS "role_permission";
+DROP TABLE IF EXISTS "user_role";
+DROP TABLE IF EXISTS "permission";
+DROP TABLE IF EXISTS "role";
+DROP TABLE IF EXISTS "user_mfa";
+DROP TABLE IF EXISTS "user_password_history";
+DROP TABLE IF EXISTS "user";
@@ -0,0 +1,127 @@
+-- 基线结构：与此前 AutoMigrate 生成的结构保持一致（表名、列、索引名相同）
+-- 全部使用 IF NOT EXISTS，已由 AutoMigrate 建好的数据库执行本迁移不会报错，旧版 user 表在此升级到当前结构
+
+-- 用户（identity.User）
+CREATE TABLE IF NOT EXISTS "user" (
+    "id"                   bigserial,
+    "uid"                  varchar(32),
+    "username"             varchar(64)  NOT NULL,
+    "email"                varchar(128),
+    "password"             varchar(255) NOT NULL,
+    "is_active"            boolean DEFAULT true,
+    "must_change_password" boolean DEFAULT false,
+    "created_at"           timestamptz,
+    "updated_at"           timestamptz,
+    "deleted_at"           timestamptz,
+    PRIMARY KEY ("id")
+);
+
+-- 旧版 user 表：补齐新增列，is_deleted / delete_at 软删除迁移为 deleted_at，并移除全局唯一索引（由仅约束未删除用户的部分唯一索引替代）
+-- 旧版 role 单值列由 000002_drop_user_role_column 迁移到 user_role 后删除
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS "must_change_password" boolean DEFAULT false;
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS "deleted_at" timestamptz;
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'user' AND column_name = 'is_deleted'
+    ) THEN
+        UPDATE "user" SET "deleted_at" = "updated_at" WHERE "is_deleted" AND "deleted_at" IS NULL;
+        ALTER TABLE "user" DROP COLUMN "is_deleted";
+    END IF;
+END $$;
+ALTER TABLE "user" DROP COLUMN IF EXISTS "delete_at";
+DROP INDEX IF EXISTS "idx_user_username";
+DROP INDEX IF EXISTS "idx_user_email";
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_uid" ON "user" ("uid");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_username_alive" ON "user" ("username") WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_email_alive" ON "user" ("email") WHERE deleted_at IS NULL AND email <> '';
+CREATE INDEX IF NOT EXISTS "idx_user_deleted_at" ON "user" ("deleted_at");
+
+-- 历史密码（identity.PasswordHistory）
+CREATE TABLE IF NOT EXISTS "user_password_history" (
+    "id"         bigserial,
+    "user_uid"   varchar(32)  NOT NULL,
+    "password"   varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_user_password_history_user_uid" ON "user_password_history" ("user_uid");
+
+-- 两步验证（auth.MFA）
+CREATE TABLE IF NOT EXISTS "user_mfa" (
+    "user_uid"       varchar(32),
+    "secret"         varchar(64) NOT NULL,
+    "enabled"        boolean DEFAULT false,
+    "recovery_codes" text,
+    "last_used_step" bigint DEFAULT 0,
+    "enabled_at"     timestamptz,
+    "created_at"     timestamptz,
+    "updated_at"     timestamptz,
+    PRIMARY KEY ("user_uid")
+);
+
+-- 角色（role.Role）
+CREATE TABLE IF NOT EXISTS "role" (
+    "id"          bigserial,
+    "code"        varchar(64) NOT NULL,
+    "name"        varchar(64) NOT NULL,
+    "sort"        bigint DEFAULT 0,
+    "is_builtin"  boolean DEFAULT false,
+    "is_active"   boolean DEFAULT true,
+    "require_mfa" boolean DEFAULT false,
+    "remark"      varchar(255),
+    "created_at"  timestamptz,
+    "updated_at"  timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_role_code" ON "role" ("code");
+
+-- 权限点（role.Permission）
+CREATE TABLE IF NOT EXISTS "permission" (
+    "id"         bigserial,
+    "code"       varchar(128) NOT NULL,
+    "name"       varchar(64)  NOT NULL,
+    "module"     varchar(64),
+    "remark"     varchar(255),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_permission_code" ON "permission" ("code");
+CREATE INDEX IF NOT EXISTS "idx_permission_module" ON "permission" ("module");
+
+-- 用户-角色（role.UserRole）
+CREATE TABLE IF NOT EXISTS "user_role" (
+    "user_uid"   varchar(32),
+    "role_id"    bigint,
+    "created_at" timestamptz,
+    PRIMARY KEY ("user_uid", "role_id")
+);
+CREATE INDEX IF NOT EXISTS "idx_user_role_role_id" ON "user_role" ("role_id");
+
+-- 角色-权限点（role.RolePermission）
+CREATE TABLE IF NOT EXISTS "role_permission" (
+    "role_id"       bigint,
+    "permission_id" bigint,
+    "created_at"    timestamptz,
+    PRIMARY KEY ("role_id", "permission_id")
+);
+CREATE INDEX IF NOT EXISTS "idx_role_permission_permission_id" ON "role_permission" ("permission_id");
+
+-- 菜单（menu.Menu）
+CREATE TABLE IF NOT EXISTS "menu" (
+    "id"         bigserial,
+    "parent_id"  bigint DEFAULT 0,
+    "name"       varchar(64) NOT NULL,
+    "path"       varchar(255),
+    "icon"       varchar(64),
+    "sort"       bigint DEFAULT 0,
+    "hidden"     boolean DEFAULT false,
+    "permission" varchar(128),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_menu_parent_id" ON "menu" ("parent_id");
@@ -0,0 +1,2 @@
+-- 旧版 user.role 单值列已迁移到 user_role 并删除，不再恢复（当前代码不读取该列）
+SELECT 1;
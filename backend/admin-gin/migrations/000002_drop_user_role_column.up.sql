@@ -0,0 +1,30 @@
+-- 旧版 user.role 单值列迁移到 user_role 中间表后删除（仅执行一次）
+-- 迁移先于 cmd/seed 执行，旧库中可能还没有内置角色：先补齐内置角色（权限点由 cmd/seed 写入并绑定默认权限）
+-- user_role 已有数据说明此前已迁移过，不再回填，避免重新授予被运营人员移除的角色
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'user' AND column_name = 'role'
+    ) THEN
+        INSERT INTO "role" ("code", "name", "sort", "is_builtin", "is_active", "require_mfa", "created_at", "updated_at")
+        VALUES
+            ('super_admin', '超级管理员', 1, true, true, true, NOW(), NOW()),
+            ('admin', '系统管理员', 2, true, true, false, NOW(), NOW()),
+            ('product_manager', '商品管理员', 3, true, true, false, NOW(), NOW()),
+            ('marketing', '营销运营', 4, true, true, false, NOW(), NOW()),
+            ('order_manager', '订单/仓储', 5, true, true, false, NOW(), NOW()),
+            ('customer_service', '客服专员', 6, true, true, false, NOW(), NOW()),
+            ('finance', '财务专员', 7, true, true, true, NOW(), NOW())
+        ON CONFLICT ("code") DO NOTHING;
+
+        IF NOT EXISTS (SELECT 1 FROM "user_role") THEN
+            INSERT INTO "user_role" ("user_uid", "role_id", "created_at")
+            SELECT u."uid", r."id", NOW() FROM "user" u JOIN "role" r ON r."code" = u."role"
+            WHERE u."uid" IS NOT NULL
+            ON CONFLICT DO NOTHING;
+        END IF;
+
+        ALTER TABLE "user" DROP COLUMN "role";
+    END IF;
+END $$;
@@ -0,0 +1,3 @@
+-- 恢复区分大小写的唯一索引（已转为小写的邮箱不会还原）
+DROP INDEX IF EXISTS "idx_user_email_lower_alive";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_email_alive" ON "user" ("email") WHERE deleted_at IS NULL AND email <> '';
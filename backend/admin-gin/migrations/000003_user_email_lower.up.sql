@@ -0,0 +1,22 @@
+-- 邮箱忽略大小写唯一：统一转为小写存储，唯一索引改建在 LOWER(email) 上
+-- 此前的唯一索引区分大小写，可能存在仅大小写不同的邮箱；找回密码按邮箱查找用户时无法确定是哪一个，须人工处理后再执行本迁移
+DO $$
+DECLARE
+    dup text;
+BEGIN
+    SELECT string_agg(d."email", ', ') INTO dup
+    FROM (
+        SELECT LOWER(TRIM("email")) AS "email" FROM "user"
+        WHERE "deleted_at" IS NULL AND TRIM("email") <> ''
+        GROUP BY 1
+        HAVING COUNT(*) > 1
+    ) d;
+    IF dup IS NOT NULL THEN
+        RAISE EXCEPTION '存在仅大小写不同的重复邮箱（%），请先修改或删除重复账号的邮箱', dup;
+    END IF;
+END $$;
+
+UPDATE "user" SET "email" = LOWER(TRIM("email")) WHERE "email" <> LOWER(TRIM("email"));
+
+DROP INDEX IF EXISTS "idx_user_email_alive";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_email_lower_alive" ON "user" (LOWER("email")) WHERE deleted_at IS NULL AND email <> '';
@@ -0,0 +1,3 @@
+-- 恢复区分大小写的唯一索引
+DROP INDEX IF EXISTS "idx_user_username_lower_alive";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_username_alive" ON "user" ("username") WHERE deleted_at IS NULL;
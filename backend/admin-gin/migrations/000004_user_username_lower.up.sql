@@ -0,0 +1,20 @@
+-- 用户名忽略大小写唯一：登录按 LOWER(username) 查找用户（与失败计数 / 锁定的 Key 一致），唯一索引改建在 LOWER(username) 上，存储保留原始大小写
+-- 此前的唯一索引区分大小写，可能存在仅大小写不同的用户名；登录时无法确定是哪一个，须人工处理后再执行本迁移
+DO $$
+DECLARE
+    dup text;
+BEGIN
+    SELECT string_agg(d."username", ', ') INTO dup
+    FROM (
+        SELECT LOWER(TRIM("username")) AS "username" FROM "user"
+        WHERE "deleted_at" IS NULL
+        GROUP BY 1
+        HAVING COUNT(*) > 1
+    ) d;
+    IF dup IS NOT NULL THEN
+        RAISE EXCEPTION '存在仅大小写不同的重复用户名（%），请先修改或删除重复账号', dup;
+    END IF;
+END $$;
+
+DROP INDEX IF EXISTS "idx_user_username_alive";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_username_lower_alive" ON "user" (LOWER("username")) WHERE deleted_at IS NULL;
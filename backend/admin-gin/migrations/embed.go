@@ -0,0 +1,8 @@
+package migrations
+
+import "embed"
+
+// FS 版本化 SQL 迁移文件（<version>_<name>.up.sql / <version>_<name>.down.sql），编译时嵌入 cmd/migrate
+//
+//go:embed *.sql
+var FS embed.FS